@@ -0,0 +1,294 @@
+package checkersbot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// What an ArchiveRecord holds
+type ArchiveRecordKind string
+
+const (
+	ARCHIVE_GAME_STATE = ArchiveRecordKind("gameState")
+	ARCHIVE_VOTE       = ArchiveRecordKind("vote")
+	ARCHIVE_RESULT     = ArchiveRecordKind("result")
+)
+
+// One line of a game's archive.  GameState is set for game state and
+// result records, Vote and VoteRev for votes.  Team is the team of the
+// bot that wrote the record.
+type ArchiveRecord struct {
+	Kind      ArchiveRecordKind `json:"kind"`
+	Time      time.Time         `json:"time"`
+	Team      TeamType          `json:"team"`
+	GameState *GameState        `json:"gameState,omitempty"`
+	Vote      *OutgoingVotes    `json:"vote,omitempty"`
+	VoteRev   string            `json:"voteRev,omitempty"`
+}
+
+func (record ArchiveRecord) gameNumber() int {
+	if record.Vote != nil {
+		return record.Vote.GameId
+	}
+	if record.GameState != nil {
+		return record.GameState.Number
+	}
+	return 0
+}
+
+// An append-only record of games on disk, one JSON Lines file per game
+// number.  Each revision of the game doc is written once, so several
+// Games, eg, one per team, can share an archive.
+type GameArchive struct {
+	Dir string
+
+	mutex        sync.Mutex
+	lastRevs     map[int]string
+	resultsSaved map[int]bool
+}
+
+// Open the archive in dir, creating dir if needed
+func NewGameArchive(dir string) (*GameArchive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	archive := &GameArchive{
+		Dir:          dir,
+		lastRevs:     map[int]string{},
+		resultsSaved: map[int]bool{},
+	}
+	return archive, nil
+}
+
+// The file game number gameNumber is archived in, eg, game-12.jsonl
+func (archive *GameArchive) Path(gameNumber int) string {
+	return filepath.Join(archive.Dir, fmt.Sprintf("game-%d.jsonl", gameNumber))
+}
+
+// Record a revision of the game doc, unless it's already been recorded
+func (archive *GameArchive) RecordGameState(team TeamType, gameState GameState) error {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if gameState.Rev != "" && archive.lastRevs[gameState.Number] == gameState.Rev {
+		return nil
+	}
+	record := ArchiveRecord{Kind: ARCHIVE_GAME_STATE, Team: team, GameState: &gameState}
+	if err := archive.append(gameState.Number, record); err != nil {
+		return err
+	}
+	archive.lastRevs[gameState.Number] = gameState.Rev
+	return nil
+}
+
+// Record a vote the server accepted, with the rev of the vote doc
+func (archive *GameArchive) RecordVote(votes OutgoingVotes, rev string) error {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	record := ArchiveRecord{Kind: ARCHIVE_VOTE, Team: votes.TeamId, Vote: &votes, VoteRev: rev}
+	return archive.append(votes.GameId, record)
+}
+
+// Record the final GameState of a game, once per game number
+func (archive *GameArchive) RecordResult(team TeamType, gameState GameState) error {
+	archive.mutex.Lock()
+	defer archive.mutex.Unlock()
+	if archive.resultsSaved[gameState.Number] {
+		return nil
+	}
+	record := ArchiveRecord{Kind: ARCHIVE_RESULT, Team: team, GameState: &gameState}
+	if err := archive.append(gameState.Number, record); err != nil {
+		return err
+	}
+	archive.resultsSaved[gameState.Number] = true
+	return nil
+}
+
+func (archive *GameArchive) append(gameNumber int, record ArchiveRecord) error {
+
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(archive.Path(gameNumber), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+
+}
+
+// The numbers of the archived games, lowest first
+func (archive *GameArchive) GameNumbers() ([]int, error) {
+
+	paths, err := filepath.Glob(filepath.Join(archive.Dir, "game-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	numbers := []int{}
+	for _, path := range paths {
+		var number int
+		name := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+		if _, err := fmt.Sscanf(name, "game-%d", &number); err != nil {
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+
+}
+
+func (archive *GameArchive) ReadGame(gameNumber int) (ArchivedGame, error) {
+	file, err := os.Open(archive.Path(gameNumber))
+	if err != nil {
+		return ArchivedGame{}, err
+	}
+	defer file.Close()
+	archivedGame, err := ReadArchivedGame(file)
+	archivedGame.Number = gameNumber
+	return archivedGame, err
+}
+
+// Call fn with each archived game in order of game number, stopping at
+// the first error
+func (archive *GameArchive) Games(fn func(archivedGame ArchivedGame) error) error {
+	numbers, err := archive.GameNumbers()
+	if err != nil {
+		return err
+	}
+	for _, number := range numbers {
+		archivedGame, err := archive.ReadGame(number)
+		if err != nil {
+			return err
+		}
+		if err := fn(archivedGame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A game read back from the archive, with its records in the order they
+// were written
+type ArchivedGame struct {
+	Number  int
+	Records []ArchiveRecord
+}
+
+// Read the records in a game's JSON Lines file.  A last line cut short,
+// as when the bot died writing it, is left out.
+func ReadArchivedGame(r io.Reader) (ArchivedGame, error) {
+
+	archivedGame := ArchivedGame{}
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return archivedGame, err
+		}
+		complete := err == nil
+		if len(strings.TrimSpace(string(line))) > 0 {
+			record := ArchiveRecord{}
+			if jsonErr := json.Unmarshal(line, &record); jsonErr != nil {
+				if !complete {
+					break
+				}
+				return archivedGame, fmt.Errorf("archive line %v: %v", lineNumber, jsonErr)
+			}
+			if len(archivedGame.Records) == 0 {
+				archivedGame.Number = record.gameNumber()
+			}
+			archivedGame.Records = append(archivedGame.Records, record)
+		}
+		if !complete {
+			break
+		}
+	}
+	return archivedGame, nil
+
+}
+
+// The revisions of the game doc, in the order they were fetched
+func (archivedGame ArchivedGame) GameStates() []GameState {
+	gameStates := []GameState{}
+	for _, record := range archivedGame.Records {
+		if record.Kind == ARCHIVE_GAME_STATE && record.GameState != nil {
+			gameStates = append(gameStates, *record.GameState)
+		}
+	}
+	return gameStates
+}
+
+func (archivedGame ArchivedGame) Votes() []ArchiveRecord {
+	votes := []ArchiveRecord{}
+	for _, record := range archivedGame.Records {
+		if record.Kind == ARCHIVE_VOTE && record.Vote != nil {
+			votes = append(votes, record)
+		}
+	}
+	return votes
+}
+
+// The final GameState, if the game's result was recorded
+func (archivedGame ArchivedGame) Result() (gameState GameState, ok bool) {
+	for _, record := range archivedGame.Records {
+		if record.Kind == ARCHIVE_RESULT && record.GameState != nil {
+			return *record.GameState, true
+		}
+	}
+	return
+}
+
+// Archive every revision of the game doc, our votes and results.  Call it
+// before GameLoop.
+func (game *Game) SetArchive(archive *GameArchive) {
+	game.archive = archive
+}
+
+func (game *Game) Archive() *GameArchive {
+	return game.archive
+}
+
+// Archive errors are logged and otherwise ignored, the game goes on
+func (game *Game) archiveGameState(gameState GameState) {
+	if game.archive == nil {
+		return
+	}
+	if err := game.archive.RecordGameState(game.ourTeamId, gameState); err != nil {
+		game.logError("Error archiving game state", err, "fetchedGame", gameState.Number)
+	}
+}
+
+func (game *Game) archiveVote(votes OutgoingVotes, rev string) {
+	if game.archive == nil {
+		return
+	}
+	if err := game.archive.RecordVote(votes, rev); err != nil {
+		game.logError("Error archiving vote", err)
+	}
+}
+
+func (game *Game) archiveResult(gameState GameState) {
+	if game.archive == nil {
+		return
+	}
+	if err := game.archive.RecordResult(game.ourTeamId, gameState); err != nil {
+		game.logError("Error archiving result", err, "fetchedGame", gameState.Number)
+	}
+}
@@ -0,0 +1,90 @@
+package checkersbot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestGameArchiveRoundTrip(t *testing.T) {
+
+	archive, err := NewGameArchive(t.TempDir())
+	assert.True(t, err == nil)
+
+	gameState := NewReferee(AMERICAN_CHECKERS).InitialGameState()
+	gameState.Number = 3
+	gameState.Rev = "1-abc"
+
+	assert.True(t, archive.RecordGameState(RED_TEAM, gameState) == nil)
+	assert.True(t, archive.RecordGameState(BLUE_TEAM, gameState) == nil)
+	votes := OutgoingVotes{Id: "vote:user:1", TeamId: RED_TEAM, GameId: 3, Turn: 1, Locations: []int{11, 15}}
+	assert.True(t, archive.RecordVote(votes, "2-def") == nil)
+
+	gameState.Rev = "2-xyz"
+	gameState.WinningTeam = BLUE_TEAM
+	assert.True(t, archive.RecordGameState(RED_TEAM, gameState) == nil)
+	assert.True(t, archive.RecordResult(RED_TEAM, gameState) == nil)
+	assert.True(t, archive.RecordResult(BLUE_TEAM, gameState) == nil)
+
+	numbers, err := archive.GameNumbers()
+	assert.True(t, err == nil)
+	assert.DeepEquals(t, numbers, []int{3})
+
+	archivedGame, err := archive.ReadGame(3)
+	assert.True(t, err == nil)
+	assert.Equals(t, archivedGame.Number, 3)
+	assert.Equals(t, len(archivedGame.Records), 4)
+
+	gameStates := archivedGame.GameStates()
+	assert.Equals(t, len(gameStates), 2)
+	assert.Equals(t, gameStates[0].Rev, "1-abc")
+	assert.Equals(t, gameStates[0].FEN(), gameState.FEN())
+	assert.Equals(t, gameStates[1].Rev, "2-xyz")
+
+	archivedVotes := archivedGame.Votes()
+	assert.Equals(t, len(archivedVotes), 1)
+	assert.DeepEquals(t, archivedVotes[0].Vote.Locations, []int{11, 15})
+	assert.Equals(t, archivedVotes[0].VoteRev, "2-def")
+
+	result, ok := archivedGame.Result()
+	assert.True(t, ok)
+	assert.Equals(t, result.WinningTeam, BLUE_TEAM)
+
+}
+
+func TestGameArchiveGames(t *testing.T) {
+
+	archive, err := NewGameArchive(t.TempDir())
+	assert.True(t, err == nil)
+	for _, number := range []int{12, 2, 7} {
+		assert.True(t, archive.RecordGameState(RED_TEAM, GameState{Number: number, Rev: "1-a"}) == nil)
+	}
+	os.WriteFile(filepath.Join(archive.Dir, "notes.txt"), []byte("not a game"), 0644)
+
+	numbers := []int{}
+	err = archive.Games(func(archivedGame ArchivedGame) error {
+		numbers = append(numbers, archivedGame.Number)
+		return nil
+	})
+	assert.True(t, err == nil)
+	assert.DeepEquals(t, numbers, []int{2, 7, 12})
+
+}
+
+func TestReadArchivedGameTruncated(t *testing.T) {
+
+	archived := `{"kind":"vote","team":0,"vote":{"game":5,"turn":1,"locations":[9,13]},"voteRev":"1-a"}
+{"kind":"gameState","team":0,"gameState":{"number":5,"tu`
+
+	archivedGame, err := ReadArchivedGame(strings.NewReader(archived))
+	assert.True(t, err == nil)
+	assert.Equals(t, archivedGame.Number, 5)
+	assert.Equals(t, len(archivedGame.Records), 1)
+
+	_, err = ReadArchivedGame(strings.NewReader("{oops\n{}\n"))
+	assert.True(t, err != nil)
+
+}
@@ -0,0 +1,390 @@
+package checkersbot
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+type BoardImageOptions struct {
+	// The width and height of one square in pixels
+	SquareSize int
+
+	// The team whose side of the board is drawn at the bottom
+	Perspective TeamType
+
+	LightSquare  color.RGBA
+	DarkSquare   color.RGBA
+	RedPiece     color.RGBA
+	BluePiece    color.RGBA
+	PieceOutline color.RGBA
+	KingMark     color.RGBA
+	Arrow        color.RGBA
+	Capture      color.RGBA
+}
+
+func DefaultBoardImageOptions() BoardImageOptions {
+	return BoardImageOptions{
+		SquareSize:   60,
+		Perspective:  BLUE_TEAM,
+		LightSquare:  color.RGBA{0xf0, 0xd9, 0xb5, 0xff},
+		DarkSquare:   color.RGBA{0x8b, 0x5a, 0x2b, 0xff},
+		RedPiece:     color.RGBA{0xc8, 0x1e, 0x1e, 0xff},
+		BluePiece:    color.RGBA{0x1e, 0x50, 0xc8, 0xff},
+		PieceOutline: color.RGBA{0x20, 0x20, 0x20, 0xff},
+		KingMark:     color.RGBA{0xff, 0xd7, 0x00, 0xff},
+		Arrow:        color.RGBA{0x20, 0xb0, 0x40, 0xd0},
+		Capture:      color.RGBA{0xff, 0xff, 0xff, 0xe0},
+	}
+}
+
+// A picture of a position, optionally with arrows for moves.  Jumped
+// squares along an arrow's path get a capture marker.  Draw it as SVG or
+// as a PNG, or get the raw image to do something else with.
+type BoardImage struct {
+	Board   VariantBoard
+	Options BoardImageOptions
+
+	// Each arrow is a path of locations, starting square first
+	Arrows [][]int
+}
+
+func NewBoardImage(board core.Board) *BoardImage {
+	return NewVariantBoardImage(coreVariantBoard(board))
+}
+
+// A picture of a board of any variant, at its size
+func NewVariantBoardImage(board VariantBoard) *BoardImage {
+	return &BoardImage{Board: board, Options: DefaultBoardImageOptions()}
+}
+
+func NewGameStateImage(gameState GameState) *BoardImage {
+	return NewVariantBoardImage(gameState.ExportVariantBoard())
+}
+
+// Draw an arrow along the move, through every landing square of a
+// multi-jump
+func (boardImage *BoardImage) AddMoveArrow(validMove ValidMove) {
+	path := append([]int{validMove.StartLocation}, validMove.Locations...)
+	boardImage.Arrows = append(boardImage.Arrows, path)
+}
+
+// Draw an arrow along a move that was played
+func (boardImage *BoardImage) AddHistoryArrow(move MoveHistory) {
+	boardImage.Arrows = append(boardImage.Arrows, move.Locations)
+}
+
+func (boardImage *BoardImage) geometry() Geometry {
+	return boardImage.Board.Variant.Geometry()
+}
+
+func (boardImage *BoardImage) size() int {
+	return boardImage.Options.SquareSize * boardImage.geometry().Size
+}
+
+// The top left corner of a square in pixels
+func (boardImage *BoardImage) squareOrigin(row, col int) (x, y int) {
+	if boardImage.Options.Perspective == RED_TEAM {
+		last := boardImage.geometry().Size - 1
+		row, col = last-row, last-col
+	}
+	return col * boardImage.Options.SquareSize, row * boardImage.Options.SquareSize
+}
+
+func (boardImage *BoardImage) locationCenter(location int) (x, y float64) {
+	loc := boardImage.geometry().CoreLocation(location)
+	originX, originY := boardImage.squareOrigin(loc.Row(), loc.Col())
+	half := float64(boardImage.Options.SquareSize) / 2
+	return float64(originX) + half, float64(originY) + half
+}
+
+// The squares jumped over along all the arrows.  A leg of more than two
+// rows is a flying king's, which jumped whichever square along it has a
+// piece on it.
+func (boardImage *BoardImage) captureLocations() (locations []int) {
+	geometry := boardImage.geometry()
+	for _, path := range boardImage.Arrows {
+		for i := 1; i < len(path); i++ {
+			from := geometry.CoreLocation(path[i-1])
+			to := geometry.CoreLocation(path[i])
+			distance := abs(to.Row() - from.Row())
+			if distance < 2 || distance != abs(to.Col()-from.Col()) {
+				continue
+			}
+			rowStep, colStep := (to.Row()-from.Row())/distance, (to.Col()-from.Col())/distance
+			for step := 1; step < distance; step++ {
+				jumped := geometry.Location(core.NewLocation(from.Row()+step*rowStep, from.Col()+step*colStep))
+				if distance == 2 || boardImage.Board.PieceAt(jumped) != core.EMPTY {
+					locations = append(locations, jumped)
+				}
+			}
+		}
+	}
+	return
+}
+
+func (boardImage *BoardImage) pieceColor(piece core.Piece) (fill color.RGBA, king bool, ok bool) {
+	switch piece {
+	case core.BLACK:
+		return boardImage.Options.RedPiece, false, true
+	case core.BLACK_KING:
+		return boardImage.Options.RedPiece, true, true
+	case core.RED:
+		return boardImage.Options.BluePiece, false, true
+	case core.RED_KING:
+		return boardImage.Options.BluePiece, true, true
+	}
+	return
+}
+
+func (boardImage *BoardImage) SVG(w io.Writer) error {
+
+	options := boardImage.Options
+	squareSize := options.SquareSize
+	pieceRadius := float64(squareSize) * 0.4
+	strokeWidth := float64(squareSize) / 10
+
+	bufWriter := bufio.NewWriter(w)
+	fmt.Fprintf(bufWriter, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", boardImage.size(), boardImage.size(), boardImage.size(), boardImage.size())
+	fmt.Fprintf(bufWriter, `<defs><marker id="arrowhead" markerWidth="4" markerHeight="4" refX="2" refY="2" orient="auto"><path d="M0,0 L4,2 L0,4 z" %v/></marker></defs>`+"\n", svgFill(options.Arrow))
+
+	for row := 0; row < boardImage.geometry().Size; row++ {
+		for col := 0; col < boardImage.geometry().Size; col++ {
+			x, y := boardImage.squareOrigin(row, col)
+			squareColor := options.LightSquare
+			if (row+col)%2 == 1 {
+				squareColor = options.DarkSquare
+			}
+			fmt.Fprintf(bufWriter, `<rect x="%d" y="%d" width="%d" height="%d" %v/>`+"\n", x, y, squareSize, squareSize, svgFill(squareColor))
+		}
+	}
+
+	for location := 1; location <= boardImage.geometry().Squares(); location++ {
+		fill, king, ok := boardImage.pieceColor(boardImage.Board.PieceAt(location))
+		if !ok {
+			continue
+		}
+		cx, cy := boardImage.locationCenter(location)
+		fmt.Fprintf(bufWriter, `<circle cx="%g" cy="%g" r="%g" %v stroke="%v" stroke-width="2"/>`+"\n", cx, cy, pieceRadius, svgFill(fill), svgHex(options.PieceOutline))
+		if king {
+			fmt.Fprintf(bufWriter, `<circle cx="%g" cy="%g" r="%g" %v/>`+"\n", cx, cy, pieceRadius/2.5, svgFill(options.KingMark))
+		}
+	}
+
+	for _, location := range boardImage.captureLocations() {
+		cx, cy := boardImage.locationCenter(location)
+		arm := pieceRadius * 0.6
+		for _, sign := range []float64{1, -1} {
+			fmt.Fprintf(bufWriter, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%v" stroke-opacity="%.2f" stroke-width="%g" stroke-linecap="round"/>`+"\n",
+				cx-arm, cy-sign*arm, cx+arm, cy+sign*arm, svgHex(options.Capture), svgOpacity(options.Capture), strokeWidth)
+		}
+	}
+
+	for _, path := range boardImage.Arrows {
+		if len(path) < 2 {
+			continue
+		}
+		points := ""
+		for i, location := range path {
+			x, y := boardImage.locationCenter(location)
+			if i > 0 {
+				points += " "
+			}
+			points += fmt.Sprintf("%g,%g", x, y)
+		}
+		fmt.Fprintf(bufWriter, `<polyline points="%v" fill="none" stroke="%v" stroke-opacity="%.2f" stroke-width="%g" stroke-linejoin="round" marker-end="url(#arrowhead)"/>`+"\n",
+			points, svgHex(options.Arrow), svgOpacity(options.Arrow), strokeWidth)
+	}
+
+	fmt.Fprintf(bufWriter, "</svg>\n")
+	return bufWriter.Flush()
+
+}
+
+func (boardImage *BoardImage) PNG(w io.Writer) error {
+	return png.Encode(w, boardImage.Image())
+}
+
+// Rasterize the board
+func (boardImage *BoardImage) Image() *image.RGBA {
+
+	options := boardImage.Options
+	squareSize := options.SquareSize
+	pieceRadius := float64(squareSize) * 0.4
+	strokeWidth := float64(squareSize) / 10
+
+	img := image.NewRGBA(image.Rect(0, 0, boardImage.size(), boardImage.size()))
+	for row := 0; row < boardImage.geometry().Size; row++ {
+		for col := 0; col < boardImage.geometry().Size; col++ {
+			x, y := boardImage.squareOrigin(row, col)
+			squareColor := options.LightSquare
+			if (row+col)%2 == 1 {
+				squareColor = options.DarkSquare
+			}
+			draw.Draw(img, image.Rect(x, y, x+squareSize, y+squareSize), image.NewUniform(squareColor), image.Point{}, draw.Src)
+		}
+	}
+
+	for location := 1; location <= boardImage.geometry().Squares(); location++ {
+		fill, king, ok := boardImage.pieceColor(boardImage.Board.PieceAt(location))
+		if !ok {
+			continue
+		}
+		cx, cy := boardImage.locationCenter(location)
+		fillCircle(img, cx, cy, pieceRadius, options.PieceOutline)
+		fillCircle(img, cx, cy, pieceRadius-2, fill)
+		if king {
+			fillCircle(img, cx, cy, pieceRadius/2.5, options.KingMark)
+		}
+	}
+
+	for _, location := range boardImage.captureLocations() {
+		cx, cy := boardImage.locationCenter(location)
+		arm := pieceRadius * 0.6
+		strokeLine(img, cx-arm, cy-arm, cx+arm, cy+arm, strokeWidth, options.Capture)
+		strokeLine(img, cx-arm, cy+arm, cx+arm, cy-arm, strokeWidth, options.Capture)
+	}
+
+	for _, path := range boardImage.Arrows {
+		if len(path) < 2 {
+			continue
+		}
+		for i := 1; i < len(path); i++ {
+			x0, y0 := boardImage.locationCenter(path[i-1])
+			x1, y1 := boardImage.locationCenter(path[i])
+
+			// stop the last leg short so the line doesn't poke
+			// out of the tip of the arrowhead
+			if i == len(path)-1 {
+				x1, y1 = towards(x1, y1, x0, y0, strokeWidth*1.5)
+			}
+			strokeLine(img, x0, y0, x1, y1, strokeWidth, options.Arrow)
+		}
+		tipX, tipY := boardImage.locationCenter(path[len(path)-1])
+		fromX, fromY := boardImage.locationCenter(path[len(path)-2])
+		fillArrowhead(img, fromX, fromY, tipX, tipY, strokeWidth*2, options.Arrow)
+	}
+
+	return img
+
+}
+
+// Blend c onto the pixel at x, y
+func blendPixel(img *image.RGBA, x, y int, c color.RGBA) {
+	if !(image.Point{x, y}.In(img.Bounds())) {
+		return
+	}
+	if c.A == 0xff {
+		img.SetRGBA(x, y, c)
+		return
+	}
+	dst := img.RGBAAt(x, y)
+	alpha := uint32(c.A)
+	mix := func(src, dst uint8) uint8 {
+		return uint8((uint32(src)*alpha + uint32(dst)*(0xff-alpha)) / 0xff)
+	}
+	img.SetRGBA(x, y, color.RGBA{mix(c.R, dst.R), mix(c.G, dst.G), mix(c.B, dst.B), 0xff})
+}
+
+func fillCircle(img *image.RGBA, cx, cy, radius float64, c color.RGBA) {
+	for y := int(cy - radius); y <= int(cy+radius); y++ {
+		for x := int(cx - radius); x <= int(cx+radius); x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			if dx*dx+dy*dy <= radius*radius {
+				blendPixel(img, x, y, c)
+			}
+		}
+	}
+}
+
+// Draw a line with round caps, by filling every pixel within width/2 of
+// the segment.  Each pixel is painted once so translucent colours blend
+// evenly.
+func strokeLine(img *image.RGBA, x0, y0, x1, y1, width float64, c color.RGBA) {
+	half := width / 2
+	minX, maxX := math.Min(x0, x1)-half, math.Max(x0, x1)+half
+	minY, maxY := math.Min(y0, y1)-half, math.Max(y0, y1)+half
+	for y := int(minY); y <= int(maxY); y++ {
+		for x := int(minX); x <= int(maxX); x++ {
+			if distanceToSegment(float64(x)+0.5, float64(y)+0.5, x0, y0, x1, y1) <= half {
+				blendPixel(img, x, y, c)
+			}
+		}
+	}
+}
+
+// Fill a triangle with its tip at tipX, tipY pointing away from fromX, fromY
+func fillArrowhead(img *image.RGBA, fromX, fromY, tipX, tipY, size float64, c color.RGBA) {
+	length := math.Hypot(tipX-fromX, tipY-fromY)
+	if length == 0 {
+		return
+	}
+	ux, uy := (tipX-fromX)/length, (tipY-fromY)/length
+	baseX, baseY := tipX-ux*size*1.5, tipY-uy*size*1.5
+	leftX, leftY := baseX-uy*size, baseY+ux*size
+	rightX, rightY := baseX+uy*size, baseY-ux*size
+
+	minX := math.Min(tipX, math.Min(leftX, rightX))
+	maxX := math.Max(tipX, math.Max(leftX, rightX))
+	minY := math.Min(tipY, math.Min(leftY, rightY))
+	maxY := math.Max(tipY, math.Max(leftY, rightY))
+	for y := int(minY); y <= int(maxY); y++ {
+		for x := int(minX); x <= int(maxX); x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			d1 := cross(px, py, tipX, tipY, leftX, leftY)
+			d2 := cross(px, py, leftX, leftY, rightX, rightY)
+			d3 := cross(px, py, rightX, rightY, tipX, tipY)
+			hasNegative := d1 < 0 || d2 < 0 || d3 < 0
+			hasPositive := d1 > 0 || d2 > 0 || d3 > 0
+			if !(hasNegative && hasPositive) {
+				blendPixel(img, x, y, c)
+			}
+		}
+	}
+}
+
+func cross(px, py, ax, ay, bx, by float64) float64 {
+	return (px-bx)*(ay-by) - (ax-bx)*(py-by)
+}
+
+func distanceToSegment(px, py, x0, y0, x1, y1 float64) float64 {
+	dx, dy := x1-x0, y1-y0
+	lengthSquared := dx*dx + dy*dy
+	t := 0.0
+	if lengthSquared > 0 {
+		t = math.Max(0, math.Min(1, ((px-x0)*dx+(py-y0)*dy)/lengthSquared))
+	}
+	return math.Hypot(px-(x0+t*dx), py-(y0+t*dy))
+}
+
+// Move x, y the given distance towards toX, toY
+func towards(x, y, toX, toY, distance float64) (float64, float64) {
+	length := math.Hypot(toX-x, toY-y)
+	if length <= distance {
+		return toX, toY
+	}
+	return x + (toX-x)/length*distance, y + (toY-y)/length*distance
+}
+
+func svgHex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func svgOpacity(c color.RGBA) float64 {
+	return float64(c.A) / 0xff
+}
+
+func svgFill(c color.RGBA) string {
+	if c.A == 0xff {
+		return fmt.Sprintf(`fill="%v"`, svgHex(c))
+	}
+	return fmt.Sprintf(`fill="%v" fill-opacity="%.2f"`, svgHex(c), svgOpacity(c))
+}
@@ -0,0 +1,80 @@
+package checkersbot
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestBoardImageSVG(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("W:WK27:B23,14")
+	boardImage := NewGameStateImage(gameState)
+	boardImage.AddMoveArrow(ValidMove{StartLocation: 27, Locations: []int{18, 9}})
+
+	buf := &bytes.Buffer{}
+	assert.True(t, boardImage.SVG(buf) == nil)
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, "<svg "))
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+	assert.Equals(t, strings.Count(svg, "<rect "), 64)
+
+	// 3 pieces plus the crown on the king
+	assert.Equals(t, strings.Count(svg, "<circle "), 4)
+
+	// an X on each of the two jumped pieces
+	assert.Equals(t, strings.Count(svg, "<line "), 4)
+	assert.Equals(t, strings.Count(svg, "<polyline "), 1)
+
+}
+
+func TestBoardImagePNG(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("W:WK27:B23,14")
+	boardImage := NewGameStateImage(gameState)
+	boardImage.AddMoveArrow(ValidMove{StartLocation: 27, Locations: []int{18, 9}})
+
+	buf := &bytes.Buffer{}
+	assert.True(t, boardImage.PNG(buf) == nil)
+	img, err := png.Decode(buf)
+	assert.True(t, err == nil)
+	assert.Equals(t, img.Bounds().Dx(), 480)
+
+	options := boardImage.Options
+	rgba := boardImage.Image()
+
+	// the edge of the red man on 23, away from the capture marker and arrow
+	cx, cy := boardImage.locationCenter(23)
+	assert.Equals(t, rgba.RGBAAt(int(cx), int(cy+20)), options.RedPiece)
+
+	// the crown on the blue king on 27 is under the start of the arrow,
+	// so check a light square instead
+	assert.Equals(t, rgba.RGBAAt(5, 5), options.LightSquare)
+
+	// from RED's side, square 1 is in the bottom right corner
+	boardImage.Options.Perspective = RED_TEAM
+	x, y := boardImage.locationCenter(1)
+	assert.True(t, x > 360 && y > 420)
+
+}
+
+func TestBoardImageInternational(t *testing.T) {
+
+	gameState, err := NewVariantGameStateFromFEN(INTERNATIONAL_DRAUGHTS, "W:WK46:B23,32")
+	assert.True(t, err == nil)
+	boardImage := NewGameStateImage(gameState)
+	boardImage.Options.SquareSize = 20
+	assert.Equals(t, boardImage.Image().Bounds().Dx(), 200)
+
+	// the flying king takes 32 on its way from 46 to 28, then 23
+	boardImage.AddMoveArrow(ValidMove{StartLocation: 46, Locations: []int{28, 19}})
+	assert.DeepEquals(t, boardImage.captureLocations(), []int{32, 23})
+
+	buf := &bytes.Buffer{}
+	assert.True(t, boardImage.SVG(buf) == nil)
+	assert.Equals(t, strings.Count(buf.String(), "<rect "), 100)
+
+}
@@ -0,0 +1,176 @@
+// Command line tools for checkers bots.
+//
+// Usage:
+//
+//	cbot tablebase -pieces 4 -out endgame.cbtb
+//	cbot play -team RED -syncGatewayUrl http://localhost:4984/checkers
+//	cbot gif -pdn game.pdn -out game.gif
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/couchbaselabs/logg"
+	cbot "github.com/tleyden/checkers-bot"
+)
+
+type subcommand struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var subcommands = []subcommand{
+	{"tablebase", "generate an endgame tablebase", runTablebase},
+	{"play", "play a game by hand from the terminal", runPlay},
+	{"gif", "make an animated GIF replay of a game", runGIF},
+}
+
+func main() {
+
+	logg.LogKeys["CHECKERSBOT"] = true
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range subcommands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "cbot %v: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	usage()
+	os.Exit(2)
+
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: cbot <command> [arguments]\n\ncommands:\n")
+	for _, cmd := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-12v %v\n", cmd.name, cmd.description)
+	}
+}
+
+func runTablebase(args []string) error {
+
+	flags := flag.NewFlagSet("tablebase", flag.ExitOnError)
+	pieces := flags.Int("pieces", 4, "Generate all positions with up to this many pieces")
+	out := flags.String("out", "endgame.cbtb", "The file to write the tablebase to")
+	flags.Parse(args)
+
+	tablebase, err := cbot.GenerateTablebase(*pieces)
+	if err != nil {
+		return err
+	}
+	if err := tablebase.Save(*out); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %v decisive positions with up to %v pieces to %v\n", tablebase.Len(), *pieces, *out)
+	return nil
+
+}
+
+func runPlay(args []string) error {
+
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	team := flags.String("team", "RED", "The team, either 'RED' or 'BLUE'")
+	serverUrl := flags.String("syncGatewayUrl", cbot.DEFAULT_SERVER_URL, "The server URL, eg: http://foo.com:4984/checkers")
+	monitoringAddr := flags.String("monitoringAddr", "", "The address to serve metrics on, eg: :9100.  Empty to disable it")
+	archiveDir := flags.String("archiveDir", "", "The directory to archive games in as JSON Lines.  Empty to disable it")
+	flags.Parse(args)
+
+	// the board and prompts go to the terminal, keep the log quiet
+	logg.LogKeys["CHECKERSBOT"] = false
+
+	var teamId cbot.TeamType
+	switch *team {
+	case "RED":
+		teamId = cbot.RED_TEAM
+	case "BLUE":
+		teamId = cbot.BLUE_TEAM
+	default:
+		return fmt.Errorf("invalid team %q, expected RED or BLUE", *team)
+	}
+
+	game := cbot.NewGame(teamId, cbot.NewConsoleThinker())
+	game.SetServerUrl(*serverUrl)
+	game.SetFeedType(cbot.LONGPOLL)
+	if *monitoringAddr != "" {
+		if _, err := game.StartMonitoring(*monitoringAddr); err != nil {
+			return err
+		}
+	}
+	if *archiveDir != "" {
+		archive, err := cbot.NewGameArchive(*archiveDir)
+		if err != nil {
+			return err
+		}
+		game.SetArchive(archive)
+	}
+	game.GameLoop()
+	return nil
+
+}
+
+func runGIF(args []string) error {
+
+	flags := flag.NewFlagSet("gif", flag.ExitOnError)
+	pdnPath := flags.String("pdn", "", "A PDN file with the game to replay")
+	jsonPath := flags.String("json", "", "A game:checkers JSON doc with the game to replay")
+	out := flags.String("out", "game.gif", "The file to write the GIF to")
+	delay := flags.Duration("delay", time.Second, "How long to show each move")
+	squareSize := flags.Int("squareSize", 60, "The size of a board square in pixels")
+	flags.Parse(args)
+
+	var moves []cbot.MoveHistory
+	switch {
+	case *pdnPath != "":
+		file, err := os.Open(*pdnPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		pdnGame, err := cbot.ParsePDNGame(file)
+		if err != nil {
+			return err
+		}
+		moves = pdnGame.Moves
+	case *jsonPath != "":
+		jsonBytes, err := ioutil.ReadFile(*jsonPath)
+		if err != nil {
+			return err
+		}
+		moves = cbot.NewGameStateFromString(string(jsonBytes)).Moves
+	default:
+		return fmt.Errorf("give either -pdn or -json")
+	}
+
+	options := cbot.DefaultReplayGIFOptions()
+	options.FrameDelay = *delay
+	options.Board.SquareSize = *squareSize
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := cbot.WriteReplayGIF(file, moves, options); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %v moves to %v\n", len(moves), *out)
+	return nil
+
+}
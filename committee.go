@@ -0,0 +1,276 @@
+package checkersbot
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/couchbaselabs/logg"
+)
+
+type VotingMethod int
+
+const (
+	// Each member votes for its choice, most votes wins
+	MAJORITY_VOTE = VotingMethod(iota)
+
+	// Like MAJORITY_VOTE, but each vote counts for the member's Weight
+	WEIGHTED_VOTE
+
+	// Members rank the moves, with n the number of different moves on
+	// the ballots a move gets n points for a first place, n-1 for second
+	// place, etc, scaled by the member's Weight.  Members that aren't
+	// MoveRankers only rank their one choice.
+	BORDA_COUNT
+)
+
+const DEFAULT_COMMITTEE_TIMEOUT = 30 * time.Second
+
+// Thinkers can implement MoveRanker to take part in a Borda count with a
+// full ranking instead of just their favourite move.
+type MoveRanker interface {
+	RankMoves(gameState GameState) (rankedMoves []ValidMove, ok bool)
+}
+
+// Gets told whenever the committee members did not all pick the same move
+type CommitteeObserver interface {
+	CommitteeDisagreed(gameState GameState, ballots []Ballot, chosen ValidMove)
+}
+
+type CommitteeMember struct {
+	Name    string
+	Thinker Thinker
+
+	// Only used by WEIGHTED_VOTE and BORDA_COUNT, zero counts as 1
+	Weight float64
+
+	// How long to wait for this member, zero means the committee's
+	// Timeout.  A member that runs out of time abstains.
+	Timeout time.Duration
+}
+
+// How one member voted on one turn
+type Ballot struct {
+	Member      string
+	RankedMoves []ValidMove
+	TimedOut    bool
+	Abstained   bool
+	Elapsed     time.Duration
+}
+
+// A Thinker that models the crowd vote inside a single bot: every member
+// thinks about the same GameState concurrently and the move is picked by
+// the committee's VotingMethod.
+type CommitteeThinker struct {
+	Members  []CommitteeMember
+	Method   VotingMethod
+	Timeout  time.Duration
+	Observer CommitteeObserver
+
+	// Members still thinking about an earlier turn, by index
+	busyMutex sync.Mutex
+	busy      map[int]bool
+}
+
+func NewCommitteeThinker(method VotingMethod, members ...CommitteeMember) *CommitteeThinker {
+	return &CommitteeThinker{
+		Members: members,
+		Method:  method,
+		Timeout: DEFAULT_COMMITTEE_TIMEOUT,
+	}
+}
+
+func (c *CommitteeThinker) Think(gameState GameState) (validMove ValidMove, ok bool) {
+
+	ballots := c.CollectBallots(gameState)
+	validMove, ok = c.Tally(ballots)
+	if !ok {
+		logg.LogTo("CHECKERSBOT", "Committee of %v members produced no move", len(c.Members))
+		return
+	}
+
+	if c.Observer != nil && !unanimous(ballots) {
+		c.Observer.CommitteeDisagreed(gameState, ballots, validMove)
+	}
+	return
+
+}
+
+// Forward GameFinished to any members that are Observers, and quit if any
+// of them wants to.
+func (c *CommitteeThinker) GameFinished(gameState GameState) (shouldQuit bool) {
+	for _, member := range c.Members {
+		if observer, ok := member.Thinker.(Observer); ok {
+			if observer.GameFinished(gameState) {
+				shouldQuit = true
+			}
+		}
+	}
+	return
+}
+
+// Run every member concurrently and wait for each one up to its timeout.
+// Thinkers can't be interrupted, so members that time out are left running
+// in the background and their late answers are thrown away.  A member
+// that is still busy with an earlier turn isn't started again, it abstains
+// as timed out, so there's never more than one call per member running.
+func (c *CommitteeThinker) CollectBallots(gameState GameState) []Ballot {
+
+	ballotChans := make([]chan Ballot, len(c.Members))
+	c.busyMutex.Lock()
+	if c.busy == nil {
+		c.busy = map[int]bool{}
+	}
+	for i, member := range c.Members {
+		if c.busy[i] {
+			continue
+		}
+		c.busy[i] = true
+		ballotChans[i] = make(chan Ballot, 1)
+		go func(i int, member CommitteeMember, name string, ballotChan chan Ballot) {
+			ballotChan <- c.vote(member, name, gameState)
+			c.busyMutex.Lock()
+			delete(c.busy, i)
+			c.busyMutex.Unlock()
+		}(i, member, c.memberName(i), ballotChans[i])
+	}
+	c.busyMutex.Unlock()
+
+	start := time.Now()
+	ballots := make([]Ballot, len(c.Members))
+	for i, member := range c.Members {
+		timeout := member.Timeout
+		if timeout == 0 {
+			timeout = c.Timeout
+		}
+		if ballotChans[i] == nil {
+			logg.LogTo("CHECKERSBOT", "Committee member %v is still thinking about an earlier turn", c.memberName(i))
+			ballots[i] = Ballot{Member: c.memberName(i), TimedOut: true, Abstained: true}
+			continue
+		}
+		remaining := timeout - time.Since(start)
+		if remaining < 0 {
+			remaining = 0
+		}
+		select {
+		case ballots[i] = <-ballotChans[i]:
+		case <-time.After(remaining):
+			logg.LogTo("CHECKERSBOT", "Committee member %v timed out after %v", c.memberName(i), timeout)
+			ballots[i] = Ballot{Member: c.memberName(i), TimedOut: true, Abstained: true, Elapsed: timeout}
+		}
+	}
+	return ballots
+
+}
+
+func (c *CommitteeThinker) vote(member CommitteeMember, name string, gameState GameState) Ballot {
+
+	start := time.Now()
+	ballot := Ballot{Member: name}
+
+	var rankedMoves []ValidMove
+	var ok bool
+	if ranker, isRanker := member.Thinker.(MoveRanker); isRanker && c.Method == BORDA_COUNT {
+		rankedMoves, ok = ranker.RankMoves(gameState)
+	} else {
+		var validMove ValidMove
+		validMove, ok = member.Thinker.Think(gameState)
+		rankedMoves = []ValidMove{validMove}
+	}
+
+	ballot.Elapsed = time.Since(start)
+	if !ok || len(rankedMoves) == 0 {
+		ballot.Abstained = true
+		return ballot
+	}
+	ballot.RankedMoves = rankedMoves
+	return ballot
+
+}
+
+// Count the ballots with the committee's VotingMethod.  Ties go to the
+// move that was voted for first, in member order.
+func (c *CommitteeThinker) Tally(ballots []Ballot) (validMove ValidMove, ok bool) {
+
+	candidates := map[string]bool{}
+	for _, ballot := range ballots {
+		if ballot.Abstained {
+			continue
+		}
+		for _, move := range ballot.RankedMoves {
+			candidates[moveKey(move)] = true
+		}
+	}
+	candidateCount := len(candidates)
+
+	scores := map[string]float64{}
+	moves := map[string]ValidMove{}
+	order := []string{}
+
+	for i, ballot := range ballots {
+		if ballot.Abstained {
+			continue
+		}
+		weight := 1.0
+		if c.Method != MAJORITY_VOTE && i < len(c.Members) && c.Members[i].Weight != 0 {
+			weight = c.Members[i].Weight
+		}
+
+		rankedMoves := ballot.RankedMoves
+		if c.Method != BORDA_COUNT {
+			rankedMoves = rankedMoves[:1]
+		}
+		for rank, move := range rankedMoves {
+			key := moveKey(move)
+			if _, seen := moves[key]; !seen {
+				moves[key] = move
+				order = append(order, key)
+			}
+			points := 1.0
+			if c.Method == BORDA_COUNT {
+				points = float64(candidateCount - rank)
+			}
+			scores[key] += points * weight
+		}
+	}
+
+	if len(order) == 0 {
+		return
+	}
+	bestKey := order[0]
+	for _, key := range order[1:] {
+		if scores[key] > scores[bestKey] {
+			bestKey = key
+		}
+	}
+	return moves[bestKey], true
+
+}
+
+func (c *CommitteeThinker) memberName(i int) string {
+	if c.Members[i].Name != "" {
+		return c.Members[i].Name
+	}
+	return fmt.Sprintf("member%d", i)
+}
+
+// Did every member that voted pick the same first choice?
+func unanimous(ballots []Ballot) bool {
+	firstChoice := ""
+	for _, ballot := range ballots {
+		if ballot.Abstained {
+			continue
+		}
+		key := moveKey(ballot.RankedMoves[0])
+		if firstChoice != "" && key != firstChoice {
+			return false
+		}
+		firstChoice = key
+	}
+	return true
+}
+
+// Identify a move by its start location and path
+func moveKey(validMove ValidMove) string {
+	return fmt.Sprintf("%v:%v", validMove.StartLocation, validMove.Locations)
+}
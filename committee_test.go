@@ -0,0 +1,145 @@
+package checkersbot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+type slowThinker struct {
+	move  ValidMove
+	delay time.Duration
+}
+
+func (s slowThinker) Think(gameState GameState) (ValidMove, bool) {
+	time.Sleep(s.delay)
+	return s.move, true
+}
+
+type rankingThinker struct {
+	ranking []ValidMove
+}
+
+func (r rankingThinker) Think(gameState GameState) (ValidMove, bool) {
+	return r.ranking[0], true
+}
+
+func (r rankingThinker) RankMoves(gameState GameState) ([]ValidMove, bool) {
+	return r.ranking, true
+}
+
+type disagreementRecorder struct {
+	ballots []Ballot
+	chosen  ValidMove
+}
+
+func (d *disagreementRecorder) CommitteeDisagreed(gameState GameState, ballots []Ballot, chosen ValidMove) {
+	d.ballots = ballots
+	d.chosen = chosen
+}
+
+var (
+	committeeMoveA = ValidMove{StartLocation: 9, Locations: []int{13}}
+	committeeMoveB = ValidMove{StartLocation: 9, Locations: []int{14}}
+	committeeMoveC = ValidMove{StartLocation: 10, Locations: []int{15}}
+)
+
+func TestCommitteeMajority(t *testing.T) {
+
+	recorder := &disagreementRecorder{}
+	committee := NewCommitteeThinker(
+		MAJORITY_VOTE,
+		CommitteeMember{Name: "a", Thinker: fixedThinker{move: committeeMoveA}},
+		CommitteeMember{Name: "b", Thinker: fixedThinker{move: committeeMoveB}},
+		CommitteeMember{Name: "b2", Thinker: fixedThinker{move: committeeMoveB}},
+	)
+	committee.Observer = recorder
+
+	validMove, ok := committee.Think(GameState{})
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(committeeMoveB))
+	assert.Equals(t, len(recorder.ballots), 3)
+	assert.Equals(t, moveKey(recorder.chosen), moveKey(committeeMoveB))
+
+}
+
+func TestCommitteeWeighted(t *testing.T) {
+
+	committee := NewCommitteeThinker(
+		WEIGHTED_VOTE,
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveA}, Weight: 3},
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveB}},
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveB}},
+	)
+	validMove, ok := committee.Think(GameState{})
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(committeeMoveA))
+
+}
+
+func TestCommitteeBorda(t *testing.T) {
+
+	// A and B split first place, but everyone ranks C second
+	// so it comes out on top of the count
+	committee := NewCommitteeThinker(
+		BORDA_COUNT,
+		CommitteeMember{Thinker: rankingThinker{[]ValidMove{committeeMoveA, committeeMoveC, committeeMoveB}}},
+		CommitteeMember{Thinker: rankingThinker{[]ValidMove{committeeMoveB, committeeMoveC, committeeMoveA}}},
+		CommitteeMember{Thinker: rankingThinker{[]ValidMove{committeeMoveC, committeeMoveA, committeeMoveB}}},
+	)
+	validMove, ok := committee.Think(GameState{})
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(committeeMoveC))
+
+}
+
+func TestCommitteeBordaSingleChoices(t *testing.T) {
+
+	// members that only pick one move still count, so B wins two to one
+	committee := NewCommitteeThinker(
+		BORDA_COUNT,
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveA}},
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveB}},
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveB}},
+	)
+	validMove, ok := committee.Think(GameState{})
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(committeeMoveB))
+
+	// and outweigh a ranker's lower places
+	committee = NewCommitteeThinker(
+		BORDA_COUNT,
+		CommitteeMember{Thinker: rankingThinker{[]ValidMove{committeeMoveA, committeeMoveB}}},
+		CommitteeMember{Thinker: fixedThinker{move: committeeMoveB}},
+	)
+	validMove, ok = committee.Think(GameState{})
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(committeeMoveB))
+
+}
+
+func TestCommitteeTimeout(t *testing.T) {
+
+	recorder := &disagreementRecorder{}
+	committee := NewCommitteeThinker(
+		MAJORITY_VOTE,
+		CommitteeMember{Name: "slow", Thinker: slowThinker{committeeMoveA, time.Second}, Timeout: 10 * time.Millisecond},
+		CommitteeMember{Name: "fast", Thinker: fixedThinker{move: committeeMoveB}},
+	)
+	committee.Observer = recorder
+
+	validMove, ok := committee.Think(GameState{})
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(committeeMoveB))
+
+	// the slow member abstained, so there was no disagreement
+	assert.Equals(t, len(recorder.ballots), 0)
+
+	// still busy with the first turn, so it isn't asked again
+	ballots := committee.CollectBallots(GameState{})
+	assert.True(t, ballots[0].TimedOut)
+	assert.Equals(t, ballots[0].Elapsed, time.Duration(0))
+	assert.False(t, ballots[1].TimedOut)
+
+}
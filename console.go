@@ -0,0 +1,203 @@
+package checkersbot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Thinkers that can give up implement Resigner.  Game quits the game loop
+// once Resigned returns true.
+type Resigner interface {
+	Resigned() bool
+}
+
+// A Thinker that asks a human at the terminal for every move.  Moves are
+// typed in standard notation, eg, 9-14 or 24x15x6, or as the index of the
+// move in the list that gets printed along with the board.
+type ConsoleThinker struct {
+	In  io.Reader
+	Out io.Writer
+
+	// Called when the player types "undo".  Crowd games can't take moves
+	// back, so when this is nil the request is just refused.
+	OnUndo func() bool
+
+	reader   *bufio.Reader
+	resigned bool
+}
+
+func NewConsoleThinker() *ConsoleThinker {
+	return &ConsoleThinker{In: os.Stdin, Out: os.Stdout}
+}
+
+func (c *ConsoleThinker) Think(gameState GameState) (validMove ValidMove, ok bool) {
+
+	if c.reader == nil {
+		c.reader = bufio.NewReader(c.In)
+	}
+
+	allValidMoves := gameState.Teams[gameState.ActiveTeam].AllValidMoves()
+	fmt.Fprintf(c.Out, "\n%v", c.render(gameState))
+	c.listMoves(allValidMoves)
+
+	for {
+		fmt.Fprintf(c.Out, "%v to move> ", gameState.ActiveTeam)
+		line, err := c.reader.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if input == "" && err != nil {
+			return
+		}
+
+		switch strings.ToLower(input) {
+		case "":
+			continue
+		case "help", "?":
+			fmt.Fprintf(c.Out, "Enter a move like 9-14 or 24x15x6, or its number from the list.\n")
+			fmt.Fprintf(c.Out, "Other commands: moves, board, undo, resign\n")
+			continue
+		case "moves":
+			c.listMoves(allValidMoves)
+			continue
+		case "board":
+			fmt.Fprintf(c.Out, "%v", c.render(gameState))
+			continue
+		case "undo":
+			if c.OnUndo != nil && c.OnUndo() {
+				fmt.Fprintf(c.Out, "Undo accepted\n")
+				return
+			}
+			fmt.Fprintf(c.Out, "Undo is not possible in this game\n")
+			continue
+		case "resign":
+			fmt.Fprintf(c.Out, "%v resigns\n", gameState.ActiveTeam)
+			c.resigned = true
+			return
+		}
+
+		validMove, err = MatchMoveInput(input, allValidMoves)
+		if err != nil {
+			fmt.Fprintf(c.Out, "%v\n", err)
+			continue
+		}
+		return validMove, true
+	}
+
+}
+
+func (c *ConsoleThinker) Resigned() bool {
+	return c.resigned
+}
+
+func (c *ConsoleThinker) GameFinished(gameState GameState) (shouldQuit bool) {
+	fmt.Fprintf(c.Out, "\n%v", c.render(gameState))
+	fmt.Fprintf(c.Out, "Game over, %v wins\n", gameState.WinningTeam)
+	return true
+}
+
+// Draw the board from the side of the team to move
+func (c *ConsoleThinker) render(gameState GameState) string {
+	options := DefaultRenderOptions
+	options.Perspective = gameState.ActiveTeam
+	return gameState.Render(options)
+}
+
+func (c *ConsoleThinker) listMoves(allValidMoves []ValidMove) {
+	for i, validMove := range allValidMoves {
+		fmt.Fprintf(c.Out, "  %2d: %v\n", i, MoveNotation(validMove.StartLocation, validMove.Locations, len(validMove.Captures) > 0))
+	}
+}
+
+// Find the valid move matching what the player typed: either an index
+// into allValidMoves or a move in standard notation.  A jump can be given
+// as just its start and end, eg, 24x6, as long as only one route fits.
+func MatchMoveInput(input string, allValidMoves []ValidMove) (validMove ValidMove, err error) {
+
+	if index, convErr := strconv.Atoi(input); convErr == nil {
+		if index < 0 || index >= len(allValidMoves) {
+			return validMove, fmt.Errorf("no move number %v, pick 0 to %v", index, len(allValidMoves)-1)
+		}
+		return allValidMoves[index], nil
+	}
+
+	start, locations, err := ParseMoveNotation(input)
+	if err != nil {
+		return
+	}
+
+	matches := []ValidMove{}
+	for _, candidate := range allValidMoves {
+		if candidate.StartLocation != start {
+			continue
+		}
+		if intsEqual(candidate.Locations, locations) {
+			return candidate, nil
+		}
+		if len(locations) == 1 && candidate.EndLocation() == locations[0] {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return validMove, fmt.Errorf("%v is not a valid move", input)
+	case 1:
+		return matches[0], nil
+	default:
+		return validMove, fmt.Errorf("%v is ambiguous, give every square of the jump, eg, %v", input, MoveNotation(start, matches[0].Locations, true))
+	}
+
+}
+
+// Parse a move like 9-14 or 24x15x6 into its start location and the
+// locations it visits.
+func ParseMoveNotation(notation string) (start int, locations []int, err error) {
+
+	separator := "-"
+	if strings.Contains(notation, "x") {
+		separator = "x"
+	}
+	parts := strings.Split(notation, separator)
+	if len(parts) < 2 {
+		return 0, nil, fmt.Errorf("can't parse move %q, expected something like 9-14 or 24x15x6", notation)
+	}
+
+	squares := make([]int, len(parts))
+	for i, part := range parts {
+		square, convErr := strconv.Atoi(strings.TrimSpace(part))
+		if convErr != nil || square < 1 || square > 32 {
+			return 0, nil, fmt.Errorf("can't parse move %q, %q is not a square from 1 to 32", notation, part)
+		}
+		squares[i] = square
+	}
+	return squares[0], squares[1:], nil
+
+}
+
+// Format a move in standard notation, eg, 9-14 or 24x15x6
+func MoveNotation(start int, locations []int, isJump bool) string {
+	separator := "-"
+	if isJump {
+		separator = "x"
+	}
+	parts := []string{strconv.Itoa(start)}
+	for _, location := range locations {
+		parts = append(parts, strconv.Itoa(location))
+	}
+	return strings.Join(parts, separator)
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package checkersbot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestParseMoveNotation(t *testing.T) {
+
+	start, locations, err := ParseMoveNotation("9-14")
+	assert.True(t, err == nil)
+	assert.Equals(t, start, 9)
+	assert.DeepEquals(t, locations, []int{14})
+
+	start, locations, err = ParseMoveNotation("24x15x6")
+	assert.True(t, err == nil)
+	assert.Equals(t, start, 24)
+	assert.DeepEquals(t, locations, []int{15, 6})
+
+	_, _, err = ParseMoveNotation("9")
+	assert.True(t, err != nil)
+	_, _, err = ParseMoveNotation("9-33")
+	assert.True(t, err != nil)
+
+	assert.Equals(t, MoveNotation(24, []int{15, 6}, true), "24x15x6")
+	assert.Equals(t, MoveNotation(9, []int{14}, false), "9-14")
+
+}
+
+func TestMatchMoveInput(t *testing.T) {
+
+	allValidMoves := []ValidMove{
+		{StartLocation: 9, Locations: []int{14}},
+		{StartLocation: 24, Locations: []int{15, 6}},
+		{StartLocation: 24, Locations: []int{15, 8}},
+		{StartLocation: 28, Locations: []int{19, 10}},
+	}
+
+	validMove, err := MatchMoveInput("9-14", allValidMoves)
+	assert.True(t, err == nil)
+	assert.Equals(t, validMove.StartLocation, 9)
+
+	validMove, err = MatchMoveInput("1", allValidMoves)
+	assert.True(t, err == nil)
+	assert.Equals(t, validMove.EndLocation(), 6)
+
+	validMove, err = MatchMoveInput("24x15x8", allValidMoves)
+	assert.True(t, err == nil)
+	assert.Equals(t, validMove.EndLocation(), 8)
+
+	// only one route from 28 to 10
+	validMove, err = MatchMoveInput("28x10", allValidMoves)
+	assert.True(t, err == nil)
+	assert.DeepEquals(t, validMove.Locations, []int{19, 10})
+
+	_, err = MatchMoveInput("9-13", allValidMoves)
+	assert.True(t, err != nil)
+	_, err = MatchMoveInput("7", allValidMoves)
+	assert.True(t, err != nil)
+
+}
+
+func TestConsoleThinker(t *testing.T) {
+
+	gameState := NewGameStateFromString(SampleJson())
+	allValidMoves := gameState.Teams[gameState.ActiveTeam].AllValidMoves()
+	wanted := allValidMoves[len(allValidMoves)-1]
+	notation := MoveNotation(wanted.StartLocation, wanted.Locations, false)
+
+	out := &bytes.Buffer{}
+	thinker := &ConsoleThinker{
+		In:  strings.NewReader("bogus\nundo\n" + notation + "\n"),
+		Out: out,
+	}
+	validMove, ok := thinker.Think(gameState)
+	assert.True(t, ok)
+	assert.Equals(t, moveKey(validMove), moveKey(wanted))
+	assert.True(t, strings.Contains(out.String(), "Undo is not possible"))
+	assert.True(t, strings.Contains(out.String(), "|+r 9|"))
+
+	thinker.In = strings.NewReader("resign\n")
+	thinker.reader = nil
+	_, ok = thinker.Think(gameState)
+	assert.False(t, ok)
+	assert.True(t, thinker.Resigned())
+
+}
@@ -92,3 +92,71 @@ func TestEqualsCoreMove(t *testing.T) {
 	assert.True(t, EqualsCoreMove(validMove, move))
 
 }
+
+// A red man on 2 can jump to 18 through 9, capturing 6 and 14, or through
+// 11, capturing 7 and 15
+func ambiguousJumpGameState() GameState {
+	return PopulateValidMoves(MustGameStateFromFEN("B:W6,7,14,15:B2"))
+}
+
+func TestFindValidMoveAmbiguous(t *testing.T) {
+
+	gameState := ambiguousJumpGameState()
+	allValidMoves := gameState.Teams[RED_TEAM].AllValidMoves()
+	assert.Equals(t, len(allValidMoves), 2)
+
+	move := core.NewMoveFromTo(GetCoreLocation(2), GetCoreLocation(18))
+	index, err := FindValidMove(move, allValidMoves)
+	assert.Equals(t, index, -1)
+	ambiguousMoveError, ok := err.(*AmbiguousMoveError)
+	assert.True(t, ok)
+	assert.Equals(t, len(ambiguousMoveError.Candidates), 2)
+
+	// the old endpoint-only lookup still takes the first match
+	found, index := CorrespondingValidMoveIndex(move, allValidMoves)
+	assert.True(t, found)
+	assert.Equals(t, index, 0)
+
+	for _, path := range gameState.LegalMovePaths() {
+		index, err := FindValidMovePath(gameState, path, allValidMoves)
+		assert.True(t, err == nil)
+		assert.DeepEquals(t, allValidMoves[index].Path(), path.Locations)
+	}
+
+	move = core.NewMoveFromTo(GetCoreLocation(2), GetCoreLocation(11))
+	_, err = FindValidMove(move, allValidMoves)
+	assert.True(t, err != nil)
+	_, ok = err.(*AmbiguousMoveError)
+	assert.False(t, ok)
+
+}
+
+func TestEqualsMovePath(t *testing.T) {
+
+	gameState := ambiguousJumpGameState()
+	validMove := ValidMove{
+		StartLocation: 2,
+		Locations:     []int{9, 18},
+		Captures:      []Capture{{TeamID: 1, PieceId: 0}, {TeamID: 1, PieceId: 2}},
+	}
+	path := MovePath{Locations: []int{2, 9, 18}, Captured: []int{6, 14}}
+	assert.True(t, EqualsMovePath(gameState, validMove, path))
+
+	// same endpoints, other route
+	path = MovePath{Locations: []int{2, 11, 18}, Captured: []int{7, 15}}
+	assert.False(t, EqualsMovePath(gameState, validMove, path))
+
+	// same route, wrong captures
+	path = MovePath{Locations: []int{2, 9, 18}, Captured: []int{7, 15}}
+	assert.False(t, EqualsMovePath(gameState, validMove, path))
+
+}
+
+func TestValidMoveEquals(t *testing.T) {
+
+	validMove := ValidMove{StartLocation: 2, Locations: []int{9, 18}, Captures: []Capture{{1, 0}, {1, 2}}}
+	assert.True(t, validMove.Equals(ValidMove{StartLocation: 2, Locations: []int{9, 18}, Captures: []Capture{{1, 2}, {1, 0}}}))
+	assert.False(t, validMove.Equals(ValidMove{StartLocation: 2, Locations: []int{11, 18}, Captures: []Capture{{1, 1}, {1, 3}}}))
+	assert.False(t, validMove.Equals(ValidMove{StartLocation: 2, Locations: []int{9, 18}, Captures: []Capture{{1, 1}, {1, 3}}}))
+
+}
@@ -0,0 +1,240 @@
+package checkersbot
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+// FEN position strings for checkers, eg, W:W21,22,K30:B1,2,3 means White
+// (BLUE) to move, White men on 21 and 22 and a king on 30, Black (RED)
+// men on 1, 2 and 3.  As in PDN, RED is Black and BLUE is White.
+
+// The position at the start of a game
+const INITIAL_FEN = "B:W21,22,23,24,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,10,11,12"
+
+type fenPiece struct {
+	location int
+	king     bool
+}
+
+// Describe the position as a FEN string.  Captured pieces are left out.
+func (gamestate GameState) FEN() string {
+	pieces := [2][]fenPiece{}
+	for teamIndex, team := range gamestate.Teams {
+		if teamIndex > 1 {
+			break
+		}
+		for _, piece := range team.Pieces {
+			if !piece.Captured {
+				pieces[teamIndex] = append(pieces[teamIndex], fenPiece{piece.Location, piece.King})
+			}
+		}
+	}
+	return formatFEN(gamestate.ActiveTeam, pieces)
+}
+
+// Build a GameState from a FEN string.  Pieces are ordered by location
+// within each team and get their index as PieceId.  There is no server
+// here to fill in ValidMoves, so every piece has none.
+func NewGameStateFromFEN(fen string) (gameState GameState, err error) {
+	return NewVariantGameStateFromFEN(AMERICAN_CHECKERS, fen)
+}
+
+// Like NewGameStateFromFEN, for a position in any variant
+func NewVariantGameStateFromFEN(variant Variant, fen string) (gameState GameState, err error) {
+
+	activeTeam, pieces, err := parseFEN(fen, variant.Geometry().Squares())
+	if err != nil {
+		return
+	}
+
+	gameState.Variant = variant
+	gameState.ActiveTeam = activeTeam
+	gameState.WinningTeam = -1
+	gameState.Teams = make([]Team, 2)
+	for teamIndex := range gameState.Teams {
+		team := &gameState.Teams[teamIndex]
+		for pieceId, fenPiece := range pieces[teamIndex] {
+			team.Pieces = append(team.Pieces, Piece{
+				Location: fenPiece.location,
+				King:     fenPiece.king,
+				PieceId:  pieceId,
+			})
+		}
+	}
+	return
+
+}
+
+// Like NewGameStateFromFEN, but panics on a bad FEN string.  Meant for
+// test fixtures, eg, MustGameStateFromFEN("W:W18:B14,K1")
+func MustGameStateFromFEN(fen string) GameState {
+	gameState, err := NewGameStateFromFEN(fen)
+	if err != nil {
+		panic(err)
+	}
+	return gameState
+}
+
+// Describe a core.Board as a FEN string with the given player to move
+func BoardFEN(board core.Board, player core.Player) string {
+	pieces := [2][]fenPiece{}
+	for location := 1; location <= 32; location++ {
+		switch board.PieceAt(GetCoreLocation(location)) {
+		case core.BLACK:
+			pieces[RED_TEAM] = append(pieces[RED_TEAM], fenPiece{location, false})
+		case core.BLACK_KING:
+			pieces[RED_TEAM] = append(pieces[RED_TEAM], fenPiece{location, true})
+		case core.RED:
+			pieces[BLUE_TEAM] = append(pieces[BLUE_TEAM], fenPiece{location, false})
+		case core.RED_KING:
+			pieces[BLUE_TEAM] = append(pieces[BLUE_TEAM], fenPiece{location, true})
+		}
+	}
+	activeTeam := RED_TEAM
+	if player == core.RED_PLAYER {
+		activeTeam = BLUE_TEAM
+	}
+	return formatFEN(activeTeam, pieces)
+}
+
+// Parse a FEN string into a core.Board and the player to move
+func BoardFromFEN(fen string) (board core.Board, player core.Player, err error) {
+	gameState, err := NewGameStateFromFEN(fen)
+	if err != nil {
+		return
+	}
+	return gameState.Export(), GetCorePlayer(gameState.ActiveTeam), nil
+}
+
+func formatFEN(activeTeam TeamType, pieces [2][]fenPiece) string {
+
+	section := func(color string, teamPieces []fenPiece) string {
+		sorted := append([]fenPiece{}, teamPieces...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].location < sorted[j].location })
+		squares := []string{}
+		for _, piece := range sorted {
+			square := strconv.Itoa(piece.location)
+			if piece.king {
+				square = "K" + square
+			}
+			squares = append(squares, square)
+		}
+		return color + strings.Join(squares, ",")
+	}
+
+	return fmt.Sprintf(
+		"%v:%v:%v",
+		fenColor(activeTeam),
+		section("W", pieces[BLUE_TEAM]),
+		section("B", pieces[RED_TEAM]),
+	)
+
+}
+
+// Parse a FEN string.  Besides single squares, ranges like K1-4 are
+// accepted, and the colour sections can come in either order.
+func parseFEN(fen string, squares int) (activeTeam TeamType, pieces [2][]fenPiece, err error) {
+
+	fen = strings.TrimSpace(fen)
+	fen = strings.TrimSuffix(fen, ".")
+	sections := strings.Split(fen, ":")
+	if len(sections) != 3 {
+		err = fmt.Errorf("bad FEN %q, expected turn:pieces:pieces", fen)
+		return
+	}
+
+	activeTeam, err = fenTeam(sections[0])
+	if err != nil {
+		err = fmt.Errorf("bad FEN %q: %v", fen, err)
+		return
+	}
+
+	occupied := map[int]bool{}
+	seen := map[TeamType]bool{}
+	for _, section := range sections[1:] {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			err = fmt.Errorf("bad FEN %q, empty piece section", fen)
+			return
+		}
+		team, teamErr := fenTeam(section[:1])
+		if teamErr != nil {
+			err = fmt.Errorf("bad FEN %q: %v", fen, teamErr)
+			return
+		}
+		if seen[team] {
+			err = fmt.Errorf("bad FEN %q, %v listed twice", fen, fenColor(team))
+			return
+		}
+		seen[team] = true
+		if section[1:] == "" {
+			continue
+		}
+		for _, square := range strings.Split(section[1:], ",") {
+			square = strings.TrimSpace(square)
+			king := strings.HasPrefix(square, "K")
+			square = strings.TrimPrefix(square, "K")
+
+			first, last, rangeErr := parseFENSquares(square, squares)
+			if rangeErr != nil {
+				err = fmt.Errorf("bad FEN %q: %v", fen, rangeErr)
+				return
+			}
+			for location := first; location <= last; location++ {
+				if occupied[location] {
+					err = fmt.Errorf("bad FEN %q, square %v is used twice", fen, location)
+					return
+				}
+				occupied[location] = true
+				pieces[team] = append(pieces[team], fenPiece{location, king})
+			}
+		}
+		sort.Slice(pieces[team], func(i, j int) bool { return pieces[team][i].location < pieces[team][j].location })
+	}
+	return
+
+}
+
+func parseFENSquares(square string, squares int) (first, last int, err error) {
+	bounds := strings.SplitN(square, "-", 2)
+	first, err = strconv.Atoi(bounds[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not a square", square)
+	}
+	last = first
+	if len(bounds) == 2 {
+		last, err = strconv.Atoi(bounds[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("%q is not a square range", square)
+		}
+	}
+	if first < 1 || last > squares || first > last {
+		return 0, 0, fmt.Errorf("%q is not within 1 to %v", square, squares)
+	}
+	return
+}
+
+func fenTeam(color string) (TeamType, error) {
+	switch strings.ToUpper(strings.TrimSpace(color)) {
+	case "B":
+		return RED_TEAM, nil
+	case "W":
+		return BLUE_TEAM, nil
+	default:
+		return -1, fmt.Errorf("%q is not a colour, expected B or W", color)
+	}
+}
+
+func fenColor(team TeamType) string {
+	switch team {
+	case RED_TEAM:
+		return "B"
+	default:
+		return "W"
+	}
+}
@@ -0,0 +1,69 @@
+package checkersbot
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+func TestGameStateFEN(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("W:W21,22,K30:B1,2,3")
+	assert.Equals(t, gameState.ActiveTeam, BLUE_TEAM)
+	assert.Equals(t, gameState.WinningTeam, TeamType(-1))
+	assert.Equals(t, len(gameState.Teams[RED_TEAM].Pieces), 3)
+	assert.Equals(t, len(gameState.Teams[BLUE_TEAM].Pieces), 3)
+	assert.True(t, gameState.Teams[BLUE_TEAM].Pieces[2].King)
+	assert.Equals(t, gameState.Teams[BLUE_TEAM].Pieces[2].Location, 30)
+	assert.Equals(t, gameState.FEN(), "W:W21,22,K30:B1,2,3")
+
+	// ranges and either section order
+	gameState = MustGameStateFromFEN("B:B1-3,K5:W32")
+	assert.Equals(t, gameState.FEN(), "B:W32:B1,2,3,K5")
+
+	gameState = MustGameStateFromFEN(INITIAL_FEN)
+	assert.Equals(t, gameState.PieceCount(), 24)
+	assert.Equals(t, gameState.FEN(), INITIAL_FEN)
+
+}
+
+func TestGameStateFENFromJson(t *testing.T) {
+
+	// captured pieces are left out
+	gameState := NewGameStateFromString(SampleJson())
+	assert.Equals(t, gameState.FEN(), "B:W21,22,23,24,25,26,27,28,29,30,31:BK1,2,3,4,5,6,7,8,9,10,K12")
+
+}
+
+func TestBoardFEN(t *testing.T) {
+
+	board, player, err := BoardFromFEN("W:W18:B14,K1")
+	assert.True(t, err == nil)
+	assert.Equals(t, player, core.RED_PLAYER)
+	assert.Equals(t, board.PieceAt(GetCoreLocation(18)), core.RED)
+	assert.Equals(t, board.PieceAt(GetCoreLocation(14)), core.BLACK)
+	assert.Equals(t, board.PieceAt(GetCoreLocation(1)), core.BLACK_KING)
+	assert.Equals(t, BoardFEN(board, player), "W:W18:BK1,14")
+
+}
+
+func TestBadFEN(t *testing.T) {
+
+	badFENs := []string{
+		"",
+		"W:W21",
+		"X:W21:B1",
+		"W:W21:W22",
+		"W:W:W22",
+		"W:W21:B21",
+		"W:W33:B1",
+		"W:Wabc:B1",
+		"W:W5-2:B1",
+	}
+	for _, fen := range badFENs {
+		_, err := NewGameStateFromFEN(fen)
+		assert.True(t, err != nil)
+	}
+
+}
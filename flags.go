@@ -9,6 +9,8 @@ type CheckersBotFlags struct {
 	SyncGatewayUrl        string
 	FeedType              FeedType
 	RandomDelayBeforeMove int
+	MonitoringAddr        string
+	ArchiveDir            string
 }
 
 type CheckersBotRawFlags struct {
@@ -16,6 +18,8 @@ type CheckersBotRawFlags struct {
 	SyncGatewayUrl        string
 	FeedString            string
 	RandomDelayBeforeMove int
+	MonitoringAddr        string
+	ArchiveDir            string
 }
 
 func GetCheckersBotRawFlags() *CheckersBotRawFlags {
@@ -50,6 +54,20 @@ func GetCheckersBotRawFlags() *CheckersBotRawFlags {
 		"The max random delay before moving in seconds.  0 to disable it",
 	)
 
+	flag.StringVar(
+		&checkersBotRawFlags.MonitoringAddr,
+		"monitoringAddr",
+		"",
+		"The address to serve metrics on, eg: :9100.  Empty to disable it",
+	)
+
+	flag.StringVar(
+		&checkersBotRawFlags.ArchiveDir,
+		"archiveDir",
+		"",
+		"The directory to archive games in as JSON Lines.  Empty to disable it",
+	)
+
 	return &checkersBotRawFlags
 
 }
@@ -84,6 +102,8 @@ func (rawFlags *CheckersBotRawFlags) GetCheckersBotFlags() CheckersBotFlags {
 	}
 
 	checkersBotFlags.RandomDelayBeforeMove = rawFlags.RandomDelayBeforeMove
+	checkersBotFlags.MonitoringAddr = rawFlags.MonitoringAddr
+	checkersBotFlags.ArchiveDir = rawFlags.ArchiveDir
 
 	return checkersBotFlags
 
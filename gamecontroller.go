@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/couchbaselabs/logg"
 	"github.com/nu7hatch/gouuid"
+	core "github.com/tleyden/checkers-core"
 	"github.com/tleyden/go-couch"
 )
 
@@ -54,24 +56,66 @@ const (
 )
 
 type Game struct {
-	thinker         Thinker
-	gameState       GameState
-	ourTeamId       TeamType
-	db              couch.Database
-	user            User
-	delayBeforeMove int
-	feedType        FeedType
-	serverUrl       string
-	lastGameDocRev  string
-	isThinking      bool
-	isThinkingMutex sync.Mutex
-	isThinkingCond  *sync.Cond
+	thinker          Thinker
+	gameState        GameState
+	ourTeamId        TeamType
+	db               couch.Database
+	server           GameServer
+	user             User
+	delayBeforeMove  int
+	feedType         FeedType
+	serverUrl        string
+	lastGameDocRev   string
+	isThinking       bool
+	isThinkingMutex  sync.Mutex
+	isThinkingCond   *sync.Cond
+	isPondering      bool
+	ponderedTurn     string
+	ponderStop       chan bool
+	ponderCache      map[string]core.Move
+	movedTurn        string
+	loopDone         chan bool
+	validMovesCheck  ValidMovesCheck
+	objective        Objective
+	observers        []LifecycleObserver
+	observersMutex   sync.Mutex
+	thinkerTimeout   time.Duration
+	feedTimeout      time.Duration
+	feedMutex        sync.Mutex
+	lastFeedActivity time.Time
+	connectionLost   bool
+	metrics          *Metrics
+	logger           Logger
+	voteTally        *VoteTally
+	voteTallyMutex   sync.Mutex
+	archive          *GameArchive
+	stats            gameStats
+	statusMutex      sync.Mutex
+	statusUserId     string
+	statusGameNumber int
+	statusTurn       int
+	statusActiveTeam TeamType
+	statusRev        string
+	lastVote         *OutgoingVotes
+	lastVoteRev      string
+	lastVoteTime     time.Time
+	lastError        error
+	lastErrorTime    time.Time
+}
+
+// The docs the game reads and writes on the server.  The game uses the
+// couch.Database it connects to, a replay can stand in for it.  The
+// changes feed is always read from the couch.Database.
+type GameServer interface {
+	Insert(doc interface{}) (id string, rev string, err error)
+	Edit(doc interface{}) (rev string, err error)
+	Retrieve(id string, doc interface{}) error
 }
 
 type Changes map[string]interface{}
 
 func NewGame(ourTeamId TeamType, thinker Thinker) *Game {
-	game := &Game{ourTeamId: ourTeamId, thinker: thinker}
+	game := &Game{ourTeamId: ourTeamId, thinker: thinker, logger: newDefaultLogger()}
 	game.isThinkingCond = sync.NewCond(&game.isThinkingMutex)
 	return game
 }
@@ -83,6 +127,7 @@ func (game *Game) GameLoop() {
 	game.InitGame()
 
 	curSinceValue := "0"
+	handledSinceValue := curSinceValue
 
 	// buffered channel is hackish workaround for cases where the
 	// it was missing revisions from the changes feed because
@@ -94,41 +139,43 @@ func (game *Game) GameLoop() {
 	// this goroutine is not reading from it, because its blocked on the
 	// call to changesChan <- changes
 	closeChan := make(chan bool)
+	game.loopDone = closeChan
 
 	handleChange := func(reader io.Reader) interface{} {
 		select {
 		case <-closeChan:
-			logg.LogTo("CHECKERSBOT", "Got msg on closeChan, returning -1. team %v: %v", game.ourTeamName(), curSinceValue)
+			game.debug("Game loop closed, stopping changes feed", "since", curSinceValue)
 			return nil // causes Changes() to return
 		default:
 		}
 
-		logg.LogTo("CHECKERSBOT", "handleChange() callback called. team %v: curSinceValue: %v.  game: %p", game.ourTeamName(), curSinceValue, game)
-		logg.LogTo("CHECKERSBOT", "# of goroutines %v", runtime.NumGoroutine())
+		game.debug("Changes feed callback", "since", curSinceValue)
+		game.feedActivity()
+		game.debug("Goroutines", "count", runtime.NumGoroutine())
 		changes := decodeChanges(reader)
 
 		changesChan <- changes // TODO: put this in select ?
 
-		logg.LogTo("CHECKERSBOT", "curSinceValue: %v changes: %v", curSinceValue, changes)
+		game.debug("Received changes", "since", curSinceValue, "changes", changes)
 		curSinceValue = getNextSinceValue(curSinceValue, changes)
+		game.metrics.SinceReceived(curSinceValue)
 		if game.feedType == NORMAL {
 			time.Sleep(time.Second * 1)
 		}
-		logg.LogTo("CHECKERSBOT", "New sinceValue for team %v: %v", game.ourTeamName(), curSinceValue)
+		game.debug("New since value", "since", curSinceValue)
 		return curSinceValue
 
 	}
 
 	go func() {
-		options := Changes{"since": curSinceValue}
-		if game.feedType == LONGPOLL {
-			options["feed"] = "longpoll"
-		}
-		game.db.Changes(handleChange, options)
-		logg.LogTo("CHECKERSBOT", "game.db.Changes() finished. team %v: %v", game.ourTeamName(), curSinceValue)
+		game.db.Changes(handleChange, game.feedOptions(curSinceValue))
+		game.debug("Changes feed finished", "since", curSinceValue)
 
 	}()
 
+	feedWatchStop := make(chan bool)
+	go game.watchFeed(feedWatchStop)
+
 	movesChan := make(chan ValidMove)
 
 	shouldQuit := false
@@ -137,32 +184,41 @@ func (game *Game) GameLoop() {
 		select {
 		case changes := <-changesChan:
 
-			logg.LogTo("CHECKERSBOT", "Got changes from changesChan, handle it. team %v: curSinceValue: %v", game.ourTeamName(), curSinceValue)
-			shouldQuit = game.handleChanges(changes, movesChan)
-			logg.LogTo("CHECKERSBOT", "Done handle changes from changesChan. team %v: curSinceValue: %v", game.ourTeamName(), curSinceValue)
+			game.debug("Handling changes", "since", curSinceValue)
+			shouldQuit = game.handleFeedChanges(changes, movesChan)
+			handledSinceValue = getNextSinceValue(handledSinceValue, changes)
+			game.metrics.SinceHandled(handledSinceValue)
+			game.debug("Done handling changes", "since", curSinceValue)
 			if shouldQuit {
-				logg.LogTo("CHECKERSBOT", "shouldQuit == true. team %v: curSinceValue: %v", game.ourTeamName(), curSinceValue)
+				game.info("Quitting game loop", "since", curSinceValue)
 				close(closeChan)
-				logg.LogTo("CHECKERSBOT", "sent true to closeChan. team %v: curSinceValue: %v", game.ourTeamName(), curSinceValue)
+				close(feedWatchStop)
+				game.stopPondering()
 				game.waitForThinkerToFinish()
 			}
 		case bestMove := <-movesChan:
-			logg.LogTo("CHECKERSBOT", "%v thinker returned move, sending vote", game.ourTeamName())
+			game.debug("Thinker returned move, sending vote", "move", bestMove)
 			outgoingVote := game.OutgoingVoteFromMove(bestMove)
 			game.PostChosenMove(outgoingVote)
-			logg.LogTo("CHECKERSBOT", "%v done sending vote", game.ourTeamName())
+			game.debug("Done sending vote")
 
 		}
 
 		if shouldQuit {
-			logg.LogTo("CHECKERSBOT", "GAME_LOOP_FINISHED.  break out of for loop. team %v: curSinceValue: %v", game.ourTeamName(), curSinceValue)
 			break
 		}
 
 	}
 
-	logg.LogTo("CHECKERSBOT", "GAME_LOOP_FINISHED .. last line. team %v: curSinceValue: %v", game.ourTeamName(), curSinceValue)
+	game.info("Game loop finished", "since", curSinceValue)
+
+}
 
+// Handle a batch of changes from the feed.  The vote tally goes first, so
+// a thinker following the crowd sees the votes that came with the game doc.
+func (game *Game) handleFeedChanges(changes Changes, movesChan chan ValidMove) (shouldQuit bool) {
+	game.handleVoteTallyChanges(changes)
+	return game.handleChanges(changes, movesChan)
 }
 
 /*
@@ -183,7 +239,7 @@ func (game *Game) waitForThinkerToFinish() {
 
 	game.isThinkingMutex.Lock()
 	defer game.isThinkingMutex.Unlock()
-	for game.isThinking {
+	for game.isThinking || game.isPondering {
 		game.isThinkingCond.Wait()
 	}
 
@@ -193,67 +249,100 @@ func (game *Game) waitForThinkerToFinish() {
 // If it has changed, and it's our turn to make a move, then call
 // the embedded Thinker to make a move or abort the game.
 func (game *Game) handleChanges(changes Changes, movesChan chan ValidMove) (shouldQuit bool) {
-	msg := fmt.Sprintf("Handle changes called for %v", game.ourTeamName())
-	logg.LogTo("CHECKERSBOT", msg)
 
 	shouldQuit = false
 	gameDocChanged := game.hasGameDocChanged(changes)
 	if gameDocChanged {
 		gameState, err := game.fetchLatestGameState()
-		msg := fmt.Sprintf("Fetched latest gameState. team %v.  Game state rev: %v", game.ourTeamName(), gameState.Rev)
-		logg.LogTo("CHECKERSBOT", msg)
+		game.debug("Fetched latest game state", "fetchedRev", gameState.Rev)
 
 		if err != nil {
-			logg.LogError(err)
-			msg := fmt.Sprintf("Due to error fetching game state team %v quitting.  Game state: %v", game.ourTeamName(), gameState)
-			logg.LogTo("CHECKERSBOT", msg)
+			game.recordError(err)
+			game.logError("Error fetching game state, quitting", err)
 			shouldQuit = true
 			return
 		}
 
+		gameState = game.withObjective(gameState)
+		game.archiveGameState(gameState)
+
+		game.debug("Game state", "fetchedGame", gameState.Number, "fetchedTurn", gameState.Turn, "activeTeam", gameState.ActiveTeam, "board", loggedBoard(gameState))
+
 		if game.finished(gameState) {
-			msg := fmt.Sprintf("Game is finished. team %v.  Game state: %v", game.ourTeamName(), gameState)
-			logg.LogTo("CHECKERSBOT", msg)
+			game.info("Game is finished", "winningTeam", gameState.WinningTeam, "board", loggedBoard(gameState))
+			game.metrics.GameFinished(gameState.Number, gameState.WinningTeam)
+			game.archiveResult(gameState)
+			game.reportGame(gameState)
 
 		}
 
 		game.updateUserGameNumberCasLoop(gameState)
+		previousGameState := game.gameState
 		game.gameState = gameState
+		game.recordGameState(gameState)
+		game.notifyGameStateEvents(previousGameState, gameState)
+		game.matchVotes(gameState)
 
 		if game.thinkerWantsToQuit(gameState) {
-			msg := fmt.Sprintf("Thinker wants to quit the %v game loop now.  Game state: %v", game.ourTeamName(), gameState)
-			logg.LogTo("CHECKERSBOT", msg)
-			logg.LogTo("CHECKERSBOT", "game #: %v team: %v", game.user.GameNumber, game.ourTeamName())
+			game.info("Thinker wants to quit the game loop now", "board", loggedBoard(gameState))
 			shouldQuit = true
 			return
 		}
 
 		if isOurTurn := game.isOurTurn(gameState); !isOurTurn {
-			logg.LogTo("CHECKERSBOT", "It's not %v turn, ignoring changes", game.ourTeamName())
+			game.debug("Not our turn, ignoring changes")
+			game.startPondering(gameState)
+			return
+		}
+
+		game.stopPondering()
+		if game.refuseValidMoves(gameState) {
+			return
+		}
+		game.metrics.TurnPlayed(gameState.Number, gameState.Turn)
+		if ponderedMove, ok := game.ponderedMove(gameState); ok {
+			game.isThinkingMutex.Lock()
+			if game.movedTurn != ponderTurnKey(gameState) {
+				game.info("Opponent played a pondered reply, moving right away", "move", ponderedMove)
+				game.movedTurn = ponderTurnKey(gameState)
+				go game.sendMove(movesChan, ponderedMove)
+			}
+			game.isThinkingMutex.Unlock()
 			return
 		}
 
 		game.isThinkingMutex.Lock()
-		if !game.isThinking {
-			logg.LogTo("CHECKERSBOT", "Call %v thinker", game.ourTeamName())
+		if game.movedTurn == ponderTurnKey(gameState) {
+			game.debug("Not calling thinker, already moved this turn")
+		} else if !game.isThinking {
+			game.debug("Calling thinker")
 			game.isThinking = true
+			timer := game.startThinkerTimer(gameState)
 			go func() {
+				thinkStart := time.Now()
 				bestMove, ok := game.thinker.Think(gameState)
-				logg.LogTo("CHECKERSBOT", "%v thinker found a move", game.ourTeamName())
+				game.metrics.ObserveThink(time.Since(thinkStart))
+				game.recordThinkTime(gameState.Number, time.Since(thinkStart))
+				if timer != nil {
+					timer.Stop()
+				}
+				game.debug("Thinker found a move", "ok", ok, "thinkTime", time.Since(thinkStart))
 				game.isThinkingMutex.Lock()
 				game.isThinking = false // TODO: use waitgroup
+				if ok {
+					game.movedTurn = ponderTurnKey(gameState)
+				}
 				game.isThinkingCond.Broadcast()
 				game.isThinkingMutex.Unlock()
 				if ok {
-					movesChan <- bestMove
-
+					game.sendMove(movesChan, bestMove)
 				} else {
-					logg.LogTo("CHECKERSBOT", "%v thinker returned not ok", game.ourTeamName())
+					game.warn("Thinker returned not ok")
 				}
 			}()
 
 		} else {
-			logg.LogTo("CHECKERSBOT", "Not claling %v thinker, already thinking in progress", game.ourTeamName())
+			game.debug("Not calling thinker, already thinking")
 		}
 		game.isThinkingMutex.Unlock()
 
@@ -261,30 +350,42 @@ func (game *Game) handleChanges(changes Changes, movesChan chan ValidMove) (shou
 	return
 }
 
-func (game Game) thinkerWantsToQuit(gameState GameState) (shouldQuit bool) {
+// Hand a move to the game loop, unless it quits first
+func (game *Game) sendMove(movesChan chan ValidMove, validMove ValidMove) {
+	select {
+	case movesChan <- validMove:
+	case <-game.loopDone:
+		game.debug("Game loop finished, dropping move", "move", validMove)
+	}
+}
+
+func (game *Game) thinkerWantsToQuit(gameState GameState) (shouldQuit bool) {
 	shouldQuit = false
+	if resigner, ok := game.thinker.(Resigner); ok && resigner.Resigned() {
+		game.info("Thinker resigned")
+		shouldQuit = true
+		return
+	}
 	if game.finished(gameState) {
 		if observer, ok := game.thinker.(Observer); ok {
 			shouldQuit = observer.GameFinished(gameState)
-			logg.LogTo("CHECKERSBOT", "%v team observer returned shouldQuit: %v", game.ourTeamName(), shouldQuit)
+			game.debug("Observer GameFinished returned", "shouldQuit", shouldQuit)
 			return
 		} else {
-			logg.LogTo("CHECKERSBOT", "thinker is not an Observer, not calling GameFinished")
+			game.debug("Thinker is not an Observer, not calling GameFinished")
 		}
 
 	}
 	return
 }
 
-func (game Game) finished(gameState GameState) bool {
-	logg.LogTo("CHECKERSBOT", "game.finished() called for team %v, gameState #: %v game.gameState #: %v", game.ourTeamName(), gameState.Number, game.gameState.Number)
+func (game *Game) finished(gameState GameState) bool {
+	gameState = game.withObjective(gameState)
 	gameHasWinner := (gameState.WinningTeam != -1)
 	finished := gameHasWinner
-	logg.LogTo("CHECKERSBOT", "game.finished() returning: %v.  team: %v", finished, game.ourTeamName())
+	game.debug("Checked whether game is finished", "fetchedGame", gameState.Number, "finished", finished)
 	if finished {
-		logg.LogTo("CHECKERSBOT", "game.finished() gamestate: %v.  team: %v", gameState, game.ourTeamName())
-		logg.LogTo("CHECKERSBOT", "wining team: %v.  ourTeam: %v", gameState.WinningTeam.String(), game.ourTeamName())
-		logg.LogTo("CHECKERSBOT", "game #: %v", gameState.Number)
+		game.debug("Game has a winner", "fetchedGame", gameState.Number, "winningTeam", gameState.WinningTeam, "objective", gameState.Objective)
 	}
 	return finished
 }
@@ -305,11 +406,12 @@ func (game *Game) CreateRemoteUser() {
 		Id:     fmt.Sprintf("user:%s", u4),
 		TeamId: game.ourTeamId,
 	}
-	newId, newRevision, err := game.db.Insert(user)
-	logg.LogTo("CHECKERSBOT", "Created new user %v rev %v team %v", newId, newRevision, game.ourTeamName())
+	newId, newRevision, err := game.gameServer().Insert(user)
 
 	user.Rev = newRevision
 	game.user = *user
+	game.recordUser(game.user)
+	game.info("Created new user", "userId", newId, "userRev", newRevision)
 
 }
 
@@ -322,6 +424,14 @@ func (game *Game) InitDbConnection() {
 	game.db = db
 }
 
+// The GameServer set by a replay, or else the couch.Database
+func (game *Game) gameServer() GameServer {
+	if game.server != nil {
+		return game.server
+	}
+	return game.db
+}
+
 func (game *Game) ServerUrl() string {
 	serverUrl := DEFAULT_SERVER_URL
 	if game.serverUrl != "" {
@@ -346,12 +456,12 @@ func (game *Game) OutgoingVoteFromMove(validMove ValidMove) (votes *OutgoingVote
 	votes = &OutgoingVotes{}
 	votesId := fmt.Sprintf("vote:%s", game.user.Id)
 
-	err := game.db.Retrieve(votesId, votes)
+	err := game.gameServer().Retrieve(votesId, votes)
 	if err != nil {
-		logg.LogTo("CHECKERSBOT", "Unable to find existing vote doc: %v", votesId)
+		game.debug("Unable to find existing vote doc", "votesId", votesId)
 	}
 
-	logg.LogTo("CHECKERSBOT", "GET votes, rev: %v", votes.Rev)
+	game.debug("Fetched vote doc", "votesRev", votes.Rev)
 
 	votes.Id = votesId
 	votes.Turn = game.gameState.Turn
@@ -369,35 +479,42 @@ func (game *Game) OutgoingVoteFromMove(validMove ValidMove) (votes *OutgoingVote
 
 func (game *Game) PostChosenMove(votes *OutgoingVotes) {
 
-	logg.LogTo("CHECKERSBOT", "Post chosen move as %v: %v", game.ourTeamName(), votes)
+	game.debug("Posting chosen move", "locations", votes.Locations)
 
 	preMoveSleepSeconds := game.calculatePreMoveSleepSeconds()
 
-	logg.LogTo("CHECKERSBOT", "Sleeping %v seconds", preMoveSleepSeconds)
+	game.debug("Sleeping before move", "seconds", preMoveSleepSeconds)
 
 	time.Sleep(time.Second * time.Duration(preMoveSleepSeconds))
 
 	if len(votes.Locations) == 0 {
-		logg.LogTo("CHECKERSBOT", "invalid move, ignoring: %v", votes)
+		game.warn("Invalid move, ignoring", "votes", votes)
 	}
 
 	var newId string
 	var newRevision string
 	var err error
-	teamName := game.ourTeamName()
-
+	postStart := time.Now()
 	if votes.Rev == "" {
-		newId, newRevision, err = game.db.Insert(votes)
-		logg.LogTo("CHECKERSBOT", "Game: %v -> Sent vote: %v as %v, Revision: %v", game.gameState.Number, teamName, newId, newRevision)
+		newId, newRevision, err = game.gameServer().Insert(votes)
+		game.info("Sent vote", "votesId", newId, "votesRev", newRevision, "locations", votes.Locations)
 	} else {
-		newRevision, err = game.db.Edit(votes)
-		logg.LogTo("CHECKERSBOT", "Game: %v -> Sent vote: %v as %v, Revision: %v", game.gameState.Number, teamName, votes.Id, newRevision)
+		newRevision, err = game.gameServer().Edit(votes)
+		game.info("Sent vote", "votesId", votes.Id, "votesRev", newRevision, "locations", votes.Locations)
 	}
 
+	game.metrics.ObserveVotePost(time.Since(postStart), err)
+
 	if err != nil {
-		logg.LogError(err)
+		game.logError("Error sending vote", err, "locations", votes.Locations)
+		game.recordError(err)
+		game.notifyObservers(func(observer LifecycleObserver) { observer.VoteRejected(*votes, err) })
 		return
 	}
+	game.recordVote(*votes, newRevision)
+	game.archiveVote(*votes, newRevision)
+	game.recordStatsVote(*votes)
+	game.notifyObservers(func(observer LifecycleObserver) { observer.VotePosted(*votes, newRevision) })
 
 }
 
@@ -411,14 +528,12 @@ func (game *Game) SetDelayBeforeMove(delayBeforeMove int) {
 // since it's possible to get a 409 conflict
 func (game *Game) updateUserGameNumberCasLoop(gameState GameState) {
 
-	logg.LogTo("CHECKERSBOT", " updateUserGameNumberCasLoop for team: %v", game.ourTeamName())
-
 	gameNumberChanged := (game.gameState.Number != gameState.Number)
 	if !gameNumberChanged {
-		logg.LogTo("CHECKERSBOT", "Game number has not changed (%v == %v), doing nothing", game.gameState.Number, gameState.Number)
+		game.debug("Game number has not changed, not updating user")
 		return
 	} else {
-		logg.LogTo("CHECKERSBOT", "Game number has changed (%v != %v)", game.gameState.Number, gameState.Number)
+		game.debug("Game number has changed, updating user", "fetchedGame", gameState.Number)
 	}
 
 	maxTries := 5
@@ -426,22 +541,21 @@ func (game *Game) updateUserGameNumberCasLoop(gameState GameState) {
 
 		// try to do a PUT
 		game.user.GameNumber = gameState.Number
-		newRevision, err := game.db.Edit(game.user)
+		newRevision, err := game.gameServer().Edit(game.user)
 		if err != nil {
-			logg.LogError(err)
-			msg := "Error updating user game number to %v"
-			logg.Log(msg, gameState.Number)
+			game.logError("Error updating user game number", err, "fetchedGame", gameState.Number)
+			game.recordError(err)
+			game.metrics.CASRetry()
 
 			if game.finished(gameState) {
-				logg.LogTo("CHECKERSBOT", "game is finished, shouldn't team %v have already quit?", game.ourTeamName())
+				game.warn("Game is finished, should have already quit")
 			}
 
 			// do a GET to get the latest user doc
 			fetchedUser, fetchedUserErr := game.fetchLatestUserDoc()
 
 			if fetchedUserErr != nil {
-				logg.LogError(fetchedUserErr)
-				logg.LogTo("CHECKERSBOT", "error fetching latest user doc")
+				game.logError("Error fetching latest user doc", fetchedUserErr)
 			} else {
 				// update the game number to the value we want
 				fetchedUser.GameNumber = gameState.Number
@@ -450,8 +564,7 @@ func (game *Game) updateUserGameNumberCasLoop(gameState GameState) {
 			}
 
 		} else {
-			logg.LogTo("CHECKERSBOT", "updated game #: %v team: %v", game.user.GameNumber, game.ourTeamName())
-			logg.LogTo("CHECKERSBOT", "user update, rev: %v", newRevision)
+			game.debug("Updated user game number", "userGame", game.user.GameNumber, "userRev", newRevision)
 			return
 		}
 
@@ -460,7 +573,7 @@ func (game *Game) updateUserGameNumberCasLoop(gameState GameState) {
 
 }
 
-func (game Game) opponentTeamId() TeamType {
+func (game *Game) opponentTeamId() TeamType {
 	switch game.ourTeamId {
 	case RED_TEAM:
 		return BLUE_TEAM
@@ -469,7 +582,7 @@ func (game Game) opponentTeamId() TeamType {
 	}
 }
 
-func (game Game) ourTeamName() string {
+func (game *Game) ourTeamName() string {
 	switch game.ourTeamId {
 	case RED_TEAM:
 		return "RED"
@@ -478,29 +591,33 @@ func (game Game) ourTeamName() string {
 	}
 }
 
-func (game Game) isOurTurn(gameState GameState) bool {
+func (game *Game) isOurTurn(gameState GameState) bool {
 	return gameState.ActiveTeam == game.ourTeamId
 }
 
 func (game *Game) hasGameDocChanged(changes Changes) bool {
-	gameDocChanged := false
+	return hasDocChanged(changes, GAME_DOC_ID)
+}
+
+func hasDocChanged(changes Changes, wantedDocId string) bool {
+	docChanged := false
 	changeResultsRaw := changes["results"]
 	if changeResultsRaw == nil {
-		return gameDocChanged
+		return docChanged
 	}
 	changeResults := changeResultsRaw.([]interface{})
 	for _, changeResultRaw := range changeResults {
 		changeResult := changeResultRaw.(map[string]interface{})
 		docIdRaw := changeResult["id"]
 		docId := docIdRaw.(string)
-		if strings.Contains(docId, GAME_DOC_ID) {
-			gameDocChanged = true
+		if strings.Contains(docId, wantedDocId) {
+			docChanged = true
 		}
 	}
-	return gameDocChanged
+	return docChanged
 }
 
-func (game Game) fetchLatestGameState() (gameState GameState, err error) {
+func (game *Game) fetchLatestGameState() (gameState GameState, err error) {
 	gameStateFetched := &GameState{}
 
 	// TODO: fix this hack
@@ -510,16 +627,16 @@ func (game Game) fetchLatestGameState() (gameState GameState, err error) {
 	// vs a null/missing value?  One way: use a pointer
 	gameStateFetched.WinningTeam = -1
 
-	err = game.db.Retrieve(GAME_DOC_ID, gameStateFetched)
+	err = game.gameServer().Retrieve(GAME_DOC_ID, gameStateFetched)
 	if err == nil {
 		gameState = *gameStateFetched
 	}
 	return
 }
 
-func (game Game) fetchLatestUserDoc() (user User, err error) {
+func (game *Game) fetchLatestUserDoc() (user User, err error) {
 	userFetched := &User{}
-	err = game.db.Retrieve(game.user.Id, userFetched)
+	err = game.gameServer().Retrieve(game.user.Id, userFetched)
 	if err == nil {
 		user = *userFetched
 	}
@@ -538,7 +655,11 @@ func getNextSinceValue(curSinceValue string, changes Changes) string {
 	if lastSeq == nil {
 		return curSinceValue
 	}
-	lastSeqStr := fmt.Sprintf("%v", lastSeq) // convert to string if it's an int
+	lastSeqStr := fmt.Sprintf("%v", lastSeq)
+	if lastSeqNum, ok := lastSeq.(float64); ok {
+		// json numbers decode as float64, don't let big ones turn into 1.2e+06
+		lastSeqStr = strconv.FormatFloat(lastSeqNum, 'f', -1, 64)
+	}
 	if lastSeqStr != "0" {
 		return lastSeqStr
 	}
@@ -589,7 +710,7 @@ func (game *Game) handleChangesWaitForNextGame(changes Changes) (shouldQuit bool
 	if gameDocChanged {
 		gameState, err := game.fetchLatestGameState()
 		if err != nil {
-			logg.LogError(err)
+			game.logError("Error fetching game state", err)
 			return
 		}
 		if gameState.Number != game.gameState.Number {
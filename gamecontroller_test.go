@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"github.com/couchbaselabs/go.assert"
 	"github.com/couchbaselabs/logg"
+	"io"
 	"log"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -109,3 +113,76 @@ func TestGetChangedRev(t *testing.T) {
 	changedRev := getChangedRev(changeResult)
 	assert.Equals(t, changedRev, rev)
 }
+
+func TestGetNextSinceValue(t *testing.T) {
+	changes := decodeChanges(strings.NewReader(`{"results":[],"last_seq":1234500}`))
+	assert.Equals(t, getNextSinceValue("0", changes), "1234500")
+	assert.Equals(t, getNextSinceValue("12", Changes{"last_seq": "*:3650"}), "*:3650")
+	assert.Equals(t, getNextSinceValue("12", Changes{"last_seq": 0}), "12")
+	assert.Equals(t, getNextSinceValue("12", Changes{}), "12")
+}
+
+type countingThinker struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (c *countingThinker) Think(gameState GameState) (ValidMove, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.count++
+	return gameState.Teams[gameState.ActiveTeam].AllValidMoves()[0], true
+}
+
+func TestMoveOncePerTurn(t *testing.T) {
+
+	thinker := &countingThinker{}
+	server := newReplayServer()
+	game := NewGame(RED_TEAM, thinker)
+	game.server = server
+	game.user = User{Id: "user:1", TeamId: RED_TEAM}
+	movesChan := make(chan ValidMove, 2)
+
+	gameState := NewReferee(AMERICAN_CHECKERS).InitialGameState()
+	gameState.Number = 1
+	gameState.Rev = "1-a"
+	server.setGameState(gameState)
+	game.handleChanges(replayChanges(1, gameState.Rev), movesChan)
+	_, ok := game.awaitReplayMove(movesChan)
+	assert.True(t, ok)
+
+	// a new revision of the game doc on the same turn
+	gameState.Rev = "2-a"
+	server.setGameState(gameState)
+	game.handleChanges(replayChanges(2, gameState.Rev), movesChan)
+	game.waitForThinkerToFinish()
+	assert.Equals(t, thinker.count, 1)
+	assert.Equals(t, len(movesChan), 0)
+
+	// a move nobody takes is dropped once the loop is done
+	game.loopDone = make(chan bool)
+	close(game.loopDone)
+	game.sendMove(make(chan ValidMove), ValidMove{})
+
+}
+
+func TestHandleChangesInternational(t *testing.T) {
+
+	thinker := &countingThinker{}
+	server := newReplayServer()
+	game := NewGame(BLUE_TEAM, thinker)
+	game.server = server
+	game.user = User{Id: "user:1", TeamId: BLUE_TEAM}
+	game.SetLogger(NewJSONLogger(io.Discard, slog.LevelDebug))
+
+	gameState := NewReferee(INTERNATIONAL_DRAUGHTS).InitialGameState()
+	gameState.Number = 1
+	gameState.Rev = "1-a"
+	server.setGameState(gameState)
+	movesChan := make(chan ValidMove, 1)
+	game.handleChanges(replayChanges(1, gameState.Rev), movesChan)
+	validMove, ok := game.awaitReplayMove(movesChan)
+	assert.True(t, ok)
+	assert.True(t, validMove.StartLocation >= 31)
+
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/couchbaselabs/logg"
 	core "github.com/tleyden/checkers-core"
+	"time"
 )
 
 // data structure that corresponds to the checkers:game json doc
@@ -18,6 +19,9 @@ type GameState struct {
 	Turn         int           `json:"turn"`
 	MoveInterval int           `json:"moveInterval"`
 	Moves        []MoveHistory `json:"moves"`
+	StartTime    time.Time     `json:"startTime"`
+	Variant      Variant       `json:"variant"`
+	Objective    Objective     `json:"objective"`
 }
 
 func NewGameStateFromString(jsonString string) GameState {
@@ -38,8 +42,15 @@ func NewGameStateFromString(jsonString string) GameState {
 	return *gameState
 }
 
+// A core.Board is 8x8, so this only makes sense for AMERICAN_CHECKERS.
+// Other variants get an empty board and an error in the log, use
+// ExportVariantBoard for them.
 func (gamestate GameState) Export() core.Board {
 	board := core.NewEmptyBoard()
+	if gamestate.Variant != AMERICAN_CHECKERS {
+		logg.LogError(fmt.Errorf("an %v board doesn't fit in a core.Board", gamestate.Variant))
+		return board
+	}
 	for teamIndex, team := range gamestate.Teams {
 		for _, piece := range team.Pieces {
 
@@ -47,7 +58,7 @@ func (gamestate GameState) Export() core.Board {
 			row := loc.Row()
 			col := loc.Col()
 
-			if piece.Captured == true {
+			if piece.Captured == true || row == -1 {
 				continue
 			}
 
@@ -79,6 +90,7 @@ type Piece struct {
 	// the locations are numbered from 1 to 32 where 1
 	// represents the top-left dark square for the red team,
 	// and 32 represents the bottom-right dark square for blue team.
+	// in INTERNATIONAL_DRAUGHTS they go from 1 to 50 in the same way.
 	Location   int         `json:"location"`
 	King       bool        `json:"king"`
 	Captured   bool        `json:"captured"`
@@ -110,9 +122,12 @@ type ValidMove struct {
 	StartLocation int
 }
 
+// A move that was played.  Unlike ValidMove, the locations include the
+// starting location, eg, [24, 15, 6] for 24->15,15->6
 type MoveHistory struct {
 	Piece     int      `json:"piece"`
 	Team      TeamType `json:"team"`
+	Turn      int      `json:"turn"`
 	Locations []int    `json:"locations"`
 }
 
@@ -144,8 +159,43 @@ func (validMove ValidMove) EndLocation() int {
 
 }
 
+// The start location followed by every landing square
+func (validMove ValidMove) Path() []int {
+	return append([]int{validMove.StartLocation}, validMove.Locations...)
+}
+
+// Same route and same captures, in any order
+func (validMove ValidMove) Equals(other ValidMove) bool {
+	if !intsEqual(validMove.Path(), other.Path()) || len(validMove.Captures) != len(other.Captures) {
+		return false
+	}
+	captured := map[Capture]int{}
+	for _, capture := range validMove.Captures {
+		captured[capture]++
+	}
+	for _, capture := range other.Captures {
+		if captured[capture] == 0 {
+			return false
+		}
+		captured[capture]--
+	}
+	return true
+}
+
 func (validMove ValidMove) String() string {
 
 	return fmt.Sprintf("%v -> %v", validMove.StartLocation, validMove.Locations)
 
 }
+
+// The number of pieces still on the board, across all teams
+func (gamestate GameState) PieceCount() (count int) {
+	for _, team := range gamestate.Teams {
+		for _, piece := range team.Pieces {
+			if !piece.Captured {
+				count++
+			}
+		}
+	}
+	return
+}
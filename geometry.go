@@ -0,0 +1,75 @@
+package checkersbot
+
+import (
+	core "github.com/tleyden/checkers-core"
+)
+
+// How the numbered dark squares of a board map to rows and columns.
+// Squares are numbered from 1 along each row, starting with the dark
+// square in the top row, which is in column 1, so on an 8x8 board 1 is
+// row 0 col 1 and 5 is row 1 col 0.  Flipped turns the board around, so
+// square 1 ends up in the bottom right corner, which is how the other
+// team sees it.
+type Geometry struct {
+	Size    int
+	Flipped bool
+}
+
+// The 8x8 board the server uses
+var STANDARD_GEOMETRY = Geometry{Size: 8}
+
+func NewGeometry(size int, flipped bool) Geometry {
+	return Geometry{Size: size, Flipped: flipped}
+}
+
+// The number of dark squares, which is the highest location
+func (g Geometry) Squares() int {
+	return g.Size * g.Size / 2
+}
+
+func (g Geometry) perRow() int {
+	return g.Size / 2
+}
+
+// The row and column of a location, or -1, -1 if there is no such square
+func (g Geometry) CoreLocation(location int) core.Location {
+
+	if location < 1 || location > g.Squares() {
+		return core.NewLocation(-1, -1)
+	}
+	index := location - 1
+	row := index / g.perRow()
+	col := 2*(index%g.perRow()) + 1 - row%2
+	if g.Flipped {
+		row, col = g.Size-1-row, g.Size-1-col
+	}
+	return core.NewLocation(row, col)
+
+}
+
+// The location of a dark square, or -1 for light squares and anything off
+// the board
+func (g Geometry) Location(loc core.Location) int {
+
+	if !g.OnBoard(loc) {
+		return -1
+	}
+	row, col := loc.Row(), loc.Col()
+	if g.Flipped {
+		row, col = g.Size-1-row, g.Size-1-col
+	}
+	if (row+col)%2 == 0 {
+		return -1
+	}
+	return row*g.perRow() + col/2 + 1
+
+}
+
+func (g Geometry) OnBoard(loc core.Location) bool {
+	return loc.Row() >= 0 && loc.Row() < g.Size && loc.Col() >= 0 && loc.Col() < g.Size
+}
+
+// The same geometry seen from the other side of the board
+func (g Geometry) Flip() Geometry {
+	return Geometry{Size: g.Size, Flipped: !g.Flipped}
+}
@@ -0,0 +1,64 @@
+package checkersbot
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+func TestGeometryBijection(t *testing.T) {
+
+	for _, size := range []int{6, 8, 10, 12} {
+		for _, flipped := range []bool{false, true} {
+			geometry := NewGeometry(size, flipped)
+			seen := map[core.Location]bool{}
+			for location := 1; location <= geometry.Squares(); location++ {
+				loc := geometry.CoreLocation(location)
+				assert.True(t, geometry.OnBoard(loc))
+				assert.Equals(t, (loc.Row()+loc.Col())%2, 1)
+				assert.False(t, seen[loc])
+				seen[loc] = true
+				assert.Equals(t, geometry.Location(loc), location)
+			}
+
+			// every dark square has a location, and no light square does
+			for row := 0; row < size; row++ {
+				for col := 0; col < size; col++ {
+					loc := core.NewLocation(row, col)
+					if (row+col)%2 == 1 {
+						assert.True(t, seen[loc])
+					} else {
+						assert.Equals(t, geometry.Location(loc), -1)
+					}
+				}
+			}
+		}
+	}
+
+}
+
+func TestGeometryStandard(t *testing.T) {
+
+	assert.Equals(t, STANDARD_GEOMETRY.Squares(), 32)
+	assert.Equals(t, STANDARD_GEOMETRY.CoreLocation(1), core.NewLocation(0, 1))
+	assert.Equals(t, STANDARD_GEOMETRY.CoreLocation(5), core.NewLocation(1, 0))
+	assert.Equals(t, STANDARD_GEOMETRY.CoreLocation(32), core.NewLocation(7, 6))
+	assert.Equals(t, STANDARD_GEOMETRY.CoreLocation(0), core.NewLocation(-1, -1))
+	assert.Equals(t, STANDARD_GEOMETRY.CoreLocation(33), core.NewLocation(-1, -1))
+	assert.Equals(t, STANDARD_GEOMETRY.Location(core.NewLocation(8, 1)), -1)
+
+	flipped := STANDARD_GEOMETRY.Flip()
+	assert.Equals(t, flipped.CoreLocation(1), core.NewLocation(7, 6))
+	assert.Equals(t, flipped.CoreLocation(32), core.NewLocation(0, 1))
+	for location := 1; location <= 32; location++ {
+		assert.Equals(t, flipped.Location(STANDARD_GEOMETRY.CoreLocation(location)), 33-location)
+	}
+
+	international := NewGeometry(10, false)
+	assert.Equals(t, international.Squares(), 50)
+	assert.Equals(t, international.CoreLocation(1), core.NewLocation(0, 1))
+	assert.Equals(t, international.CoreLocation(6), core.NewLocation(1, 0))
+	assert.Equals(t, international.CoreLocation(50), core.NewLocation(9, 8))
+
+}
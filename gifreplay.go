@@ -0,0 +1,147 @@
+package checkersbot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+type ReplayGIFOptions struct {
+	Board BoardImageOptions
+
+	// How long each move stays on screen, and the final position
+	FrameDelay      time.Duration
+	FinalFrameDelay time.Duration
+
+	// Draw an arrow along the move that led to each frame
+	Arrows bool
+
+	// Add a strip under the board with the turn number, team and move
+	Captions bool
+
+	CaptionBackground color.RGBA
+	CaptionText       color.RGBA
+}
+
+func DefaultReplayGIFOptions() ReplayGIFOptions {
+	return ReplayGIFOptions{
+		Board:             DefaultBoardImageOptions(),
+		FrameDelay:        time.Second,
+		FinalFrameDelay:   4 * time.Second,
+		Arrows:            true,
+		Captions:          true,
+		CaptionBackground: color.RGBA{0x20, 0x20, 0x20, 0xff},
+		CaptionText:       color.RGBA{0xff, 0xff, 0xff, 0xff},
+	}
+}
+
+// Write an animated GIF stepping through the moves of a game, starting
+// from the initial position.  Works with GameState.Moves or the Moves of a
+// PDNGame.  The moves are played by the Referee, going by their locations
+// and ignoring piece ids.
+func WriteReplayGIF(w io.Writer, moves []MoveHistory, options ReplayGIFOptions) error {
+
+	played := make([]MoveHistory, len(moves))
+	for i, move := range moves {
+		played[i] = move
+		played[i].Piece = -1
+	}
+	gameStates, err := NewReferee(AMERICAN_CHECKERS).GameStates(played)
+	if err != nil {
+		return err
+	}
+
+	animation := &gif.GIF{}
+	for i, gameState := range gameStates {
+		boardImage := NewGameStateImage(gameState)
+		boardImage.Options = options.Board
+		caption := "START"
+		if i > 0 {
+			move := moves[i-1]
+			if options.Arrows {
+				boardImage.AddHistoryArrow(move)
+			}
+			turn := move.Turn
+			if turn == 0 {
+				turn = i
+			}
+			caption = fmt.Sprintf("TURN %d %v %v", turn, move.Team, PDNMoveString(move))
+		}
+
+		frame := boardImage.Image()
+		if options.Captions {
+			frame = addCaption(frame, caption, options)
+		}
+
+		delay := options.FrameDelay
+		if i == len(gameStates)-1 {
+			delay = options.FinalFrameDelay
+		}
+		animation.Image = append(animation.Image, palettedFrame(frame))
+		animation.Delay = append(animation.Delay, int(delay/(10*time.Millisecond)))
+	}
+
+	return gif.EncodeAll(w, animation)
+
+}
+
+// Extend the frame with a caption strip along the bottom
+func addCaption(frame *image.RGBA, caption string, options ReplayGIFOptions) *image.RGBA {
+
+	scale := options.Board.SquareSize / 30
+	if scale < 1 {
+		scale = 1
+	}
+	textWidth, textHeight := textSize(caption, scale)
+	padding := textHeight / 2
+
+	bounds := frame.Bounds()
+	captioned := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()+textHeight+2*padding))
+	draw.Draw(captioned, captioned.Bounds(), image.NewUniform(options.CaptionBackground), image.Point{}, draw.Src)
+	draw.Draw(captioned, bounds, frame, bounds.Min, draw.Src)
+
+	x := (bounds.Dx() - textWidth) / 2
+	if x < padding {
+		x = padding
+	}
+	drawText(captioned, x, bounds.Dy()+padding, caption, scale, options.CaptionText)
+	return captioned
+
+}
+
+// Convert to a paletted image.  Board images only have a handful of
+// colours, so use exactly those when they fit in a GIF palette, which
+// keeps the colours true, and fall back to the web safe palette otherwise.
+func palettedFrame(frame *image.RGBA) *image.Paletted {
+
+	bounds := frame.Bounds()
+	seen := map[color.RGBA]bool{}
+	colors := color.Palette{}
+	for y := bounds.Min.Y; y < bounds.Max.Y && len(colors) <= 256; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := frame.RGBAAt(x, y)
+			if !seen[c] {
+				seen[c] = true
+				colors = append(colors, c)
+				if len(colors) > 256 {
+					break
+				}
+			}
+		}
+	}
+
+	if len(colors) > 256 {
+		paletted := image.NewPaletted(bounds, palette.WebSafe)
+		draw.FloydSteinberg.Draw(paletted, bounds, frame, bounds.Min)
+		return paletted
+	}
+	paletted := image.NewPaletted(bounds, colors)
+	draw.Draw(paletted, bounds, frame, bounds.Min, draw.Src)
+	return paletted
+
+}
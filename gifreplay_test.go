@@ -0,0 +1,49 @@
+package checkersbot
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+var replayTestMoves = []MoveHistory{
+	{Team: RED_TEAM, Turn: 1, Locations: []int{10, 14}},
+	{Team: BLUE_TEAM, Turn: 2, Locations: []int{23, 19}},
+	{Team: RED_TEAM, Turn: 3, Locations: []int{14, 18}},
+	{Team: BLUE_TEAM, Turn: 4, Locations: []int{22, 15}},
+}
+
+func TestReplayGIFIllegalMove(t *testing.T) {
+
+	// moving a piece that isn't there
+	err := WriteReplayGIF(&bytes.Buffer{}, []MoveHistory{{Team: RED_TEAM, Locations: []int{14, 18}}}, DefaultReplayGIFOptions())
+	_, ok := err.(*IllegalMoveError)
+	assert.True(t, ok)
+
+}
+
+func TestWriteReplayGIF(t *testing.T) {
+
+	options := DefaultReplayGIFOptions()
+	options.Board.SquareSize = 30
+	options.FrameDelay = 500 * time.Millisecond
+
+	buf := &bytes.Buffer{}
+	err := WriteReplayGIF(buf, replayTestMoves, options)
+	assert.True(t, err == nil)
+
+	animation, err := gif.DecodeAll(buf)
+	assert.True(t, err == nil)
+	assert.Equals(t, len(animation.Image), 5)
+	assert.Equals(t, animation.Delay[0], 50)
+	assert.Equals(t, animation.Delay[4], 400)
+
+	// board plus caption strip
+	bounds := animation.Image[0].Bounds()
+	assert.Equals(t, bounds.Dx(), 240)
+	assert.True(t, bounds.Dy() > 240)
+
+}
@@ -0,0 +1,102 @@
+package checkersbot
+
+// What became of one of our votes: the move our team played on the turn
+// we voted on, and whether it was the one we voted for
+type VoteOutcome struct {
+	GameNumber int         `json:"gameNumber"`
+	Turn       int         `json:"turn"`
+	Voted      []int       `json:"voted"`
+	Played     MoveHistory `json:"played"`
+	Agreed     bool        `json:"agreed"`
+}
+
+// How often the move our team played was the one we voted for, in the
+// current game and over every game since the bot started.  Only turns we
+// voted on count.
+type InfluenceStats struct {
+	GameNumber int `json:"gameNumber"`
+	GameTurns  int `json:"gameTurns"`
+	GameAgreed int `json:"gameAgreed"`
+	Turns      int `json:"turns"`
+	Agreed     int `json:"agreed"`
+}
+
+// The fraction of turns agreed on over every game, 0 before there are any
+func (stats InfluenceStats) Rate() float64 {
+	if stats.Turns == 0 {
+		return 0
+	}
+	return float64(stats.Agreed) / float64(stats.Turns)
+}
+
+// The fraction of turns agreed on in the current game
+func (stats InfluenceStats) GameRate() float64 {
+	if stats.GameTurns == 0 {
+		return 0
+	}
+	return float64(stats.GameAgreed) / float64(stats.GameTurns)
+}
+
+func (game *Game) Influence() InfluenceStats {
+	game.stats.mutex.Lock()
+	defer game.stats.mutex.Unlock()
+	return game.stats.influence()
+}
+
+// Call with the mutex held
+func (stats *gameStats) influence() InfluenceStats {
+	return InfluenceStats{
+		GameNumber: stats.gameNumber,
+		GameTurns:  stats.matchedCount,
+		GameAgreed: stats.agreedCount,
+		Turns:      stats.totalMatched,
+		Agreed:     stats.totalAgreed,
+	}
+}
+
+// Look in a new revision of the game doc for the moves our team played
+// on turns we voted on, and tell the observers how each vote fared
+func (game *Game) matchVotes(gameState GameState) {
+
+	outcomes := []VoteOutcome{}
+	stats := &game.stats
+	stats.mutex.Lock()
+	stats.forGame(gameState.Number)
+	for _, move := range gameState.Moves {
+		if move.Team != game.ourTeamId || stats.matchedTurns[move.Turn] {
+			continue
+		}
+		voted, ok := stats.votedPaths[move.Turn]
+		if !ok {
+			continue
+		}
+		stats.matchedTurns[move.Turn] = true
+		outcome := VoteOutcome{
+			GameNumber: gameState.Number,
+			Turn:       move.Turn,
+			Voted:      voted,
+			Played:     move,
+			Agreed:     intsEqual(voted, move.Locations),
+		}
+		stats.matchedCount++
+		stats.totalMatched++
+		if outcome.Agreed {
+			stats.agreedCount++
+			stats.totalAgreed++
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	influence := stats.influence()
+	stats.mutex.Unlock()
+
+	for _, outcome := range outcomes {
+		game.info("Vote outcome", "voteTurn", outcome.Turn, "voted", PDNMoveString(MoveHistory{Locations: outcome.Voted}), "played", PDNMoveString(outcome.Played), "agreed", outcome.Agreed, "agreementRate", influence.Rate())
+		game.metrics.VoteOutcome(outcome.Agreed)
+		for _, observer := range game.optionalObservers() {
+			if observer, ok := observer.(VoteOutcomeObserver); ok {
+				observer.VoteOutcome(outcome, influence)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,94 @@
+package checkersbot
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+type outcomeRecorder struct {
+	BaseLifecycleObserver
+	mutex     sync.Mutex
+	outcomes  []VoteOutcome
+	influence InfluenceStats
+}
+
+func (o *outcomeRecorder) VoteOutcome(outcome VoteOutcome, influence InfluenceStats) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.outcomes = append(o.outcomes, outcome)
+	o.influence = influence
+}
+
+func TestMatchVotes(t *testing.T) {
+
+	game := NewGame(RED_TEAM, nil)
+	metrics := game.EnableMetrics()
+	recorder := &outcomeRecorder{}
+	game.AddLifecycleObserver(recorder)
+
+	game.recordStatsVote(OutgoingVotes{GameId: 1, Turn: 1, Locations: []int{11, 15}})
+	gameState := GameState{Number: 1, Moves: []MoveHistory{
+		{Team: RED_TEAM, Turn: 1, Locations: []int{11, 15}},
+	}}
+	game.matchVotes(gameState)
+	// seeing the same move again doesn't count twice
+	game.matchVotes(gameState)
+
+	game.recordStatsVote(OutgoingVotes{GameId: 1, Turn: 3, Locations: []int{9, 13}})
+	gameState.Moves = append(gameState.Moves,
+		MoveHistory{Team: BLUE_TEAM, Turn: 2, Locations: []int{22, 18}},
+		MoveHistory{Team: RED_TEAM, Turn: 3, Locations: []int{15, 22}},
+	)
+	game.matchVotes(gameState)
+
+	assert.Equals(t, len(recorder.outcomes), 2)
+	assert.True(t, recorder.outcomes[0].Agreed)
+	assert.Equals(t, recorder.outcomes[1].Turn, 3)
+	assert.False(t, recorder.outcomes[1].Agreed)
+	assert.DeepEquals(t, recorder.outcomes[1].Voted, []int{9, 13})
+	assert.DeepEquals(t, recorder.outcomes[1].Played.Locations, []int{15, 22})
+
+	influence := game.Influence()
+	assert.Equals(t, influence, InfluenceStats{GameNumber: 1, GameTurns: 2, GameAgreed: 1, Turns: 2, Agreed: 1})
+	assert.Equals(t, influence.Rate(), 0.5)
+	assert.Equals(t, recorder.influence, influence)
+
+	// the totals carry on into the next game
+	game.recordStatsVote(OutgoingVotes{GameId: 2, Turn: 1, Locations: []int{10, 14}})
+	game.matchVotes(GameState{Number: 2, Moves: []MoveHistory{{Team: RED_TEAM, Turn: 1, Locations: []int{10, 14}}}})
+	influence = game.Influence()
+	assert.Equals(t, influence, InfluenceStats{GameNumber: 2, GameTurns: 1, GameAgreed: 1, Turns: 3, Agreed: 2})
+	assert.Equals(t, influence.GameRate(), 1.0)
+	assert.Equals(t, game.Status().Influence, influence)
+
+	buf := &bytes.Buffer{}
+	metrics.WriteTo(buf)
+	assert.True(t, strings.Contains(buf.String(), `checkersbot_vote_outcomes_total{team="RED",result="agreed"} 2`))
+	assert.True(t, strings.Contains(buf.String(), `checkersbot_vote_outcomes_total{team="RED",result="overruled"} 1`))
+
+}
+
+func TestMatchVotesDuringReplay(t *testing.T) {
+
+	pdnGame, err := ParsePDNGame(strings.NewReader(replayTestPDN))
+	assert.True(t, err == nil)
+	thinker := &struct {
+		scriptedThinker
+		*outcomeRecorder
+	}{
+		scriptedThinker{paths: map[int][]int{1: {11, 15}, 3: {15, 22}, 5: {9, 13}}},
+		&outcomeRecorder{},
+	}
+
+	_, err = ReplayPDNGame(RED_TEAM, thinker, pdnGame)
+	assert.True(t, err == nil)
+	assert.Equals(t, len(thinker.outcomes), 3)
+	assert.False(t, thinker.outcomes[2].Agreed)
+	assert.Equals(t, thinker.influence.Turns, 3)
+	assert.Equals(t, thinker.influence.Agreed, 2)
+
+}
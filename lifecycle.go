@@ -0,0 +1,208 @@
+package checkersbot
+
+import "time"
+
+// How long the changes feed can go quiet before the connection counts as
+// lost.  Longpoll requests are asked to time out well within this, see
+// feedOptions, so a quiet game still makes the feed callback regularly.
+const DEFAULT_FEED_TIMEOUT = 2 * time.Minute
+
+// Thinkers that also implement LifecycleObserver hear about everything
+// that happens during a game, not just the end of it.  Other observers
+// can be added with AddLifecycleObserver.  The Game calls these from
+// several goroutines, so they should be quick and safe to call
+// concurrently.  Embed BaseLifecycleObserver to only implement the
+// events you care about.
+type LifecycleObserver interface {
+
+	// A game with a new number showed up
+	GameStarted(gameState GameState)
+
+	// The turn number changed, for either team
+	TurnStarted(gameState GameState)
+
+	// The other team's move showed up in the game's Moves
+	OpponentMoveApplied(gameState GameState, move MoveHistory)
+
+	VotePosted(votes OutgoingVotes, rev string)
+	VoteRejected(votes OutgoingVotes, err error)
+
+	// The thinker has been going for longer than the timeout, it is left
+	// running
+	ThinkerTimedOut(gameState GameState, timeout time.Duration)
+
+	// The changes feed has been quiet since lastActivity, and came back
+	// after being down for downtime
+	ConnectionLost(lastActivity time.Time)
+	ConnectionRestored(downtime time.Duration)
+}
+
+// Ignores every event
+type BaseLifecycleObserver struct{}
+
+func (BaseLifecycleObserver) GameStarted(gameState GameState)                            {}
+func (BaseLifecycleObserver) TurnStarted(gameState GameState)                            {}
+func (BaseLifecycleObserver) OpponentMoveApplied(gameState GameState, move MoveHistory)  {}
+func (BaseLifecycleObserver) VotePosted(votes OutgoingVotes, rev string)                 {}
+func (BaseLifecycleObserver) VoteRejected(votes OutgoingVotes, err error)                {}
+func (BaseLifecycleObserver) ThinkerTimedOut(gameState GameState, timeout time.Duration) {}
+func (BaseLifecycleObserver) ConnectionLost(lastActivity time.Time)                      {}
+func (BaseLifecycleObserver) ConnectionRestored(downtime time.Duration)                  {}
+
+func (game *Game) AddLifecycleObserver(observer LifecycleObserver) {
+	game.observersMutex.Lock()
+	defer game.observersMutex.Unlock()
+	game.observers = append(game.observers, observer)
+}
+
+// How long the thinker gets before ThinkerTimedOut fires.  By default
+// it's the game's moveInterval, 0 turns it off when there is none.
+func (game *Game) SetThinkerTimeout(timeout time.Duration) {
+	game.thinkerTimeout = timeout
+}
+
+func (game *Game) SetFeedTimeout(timeout time.Duration) {
+	game.feedTimeout = timeout
+}
+
+// The thinker, if it's a LifecycleObserver, and the added observers
+func (game *Game) lifecycleObservers() []LifecycleObserver {
+	game.observersMutex.Lock()
+	defer game.observersMutex.Unlock()
+	observers := []LifecycleObserver{}
+	if observer, ok := game.thinker.(LifecycleObserver); ok {
+		observers = append(observers, observer)
+	}
+	return append(observers, game.observers...)
+}
+
+func (game *Game) notifyObservers(event func(observer LifecycleObserver)) {
+	for _, observer := range game.lifecycleObservers() {
+		event(observer)
+	}
+}
+
+// Fire the events for a new revision of the game doc, compared to the
+// previous one we saw
+func (game *Game) notifyGameStateEvents(previous GameState, gameState GameState) {
+
+	firstSeen := previous.Rev == ""
+	newGame := firstSeen || previous.Number != gameState.Number
+	if newGame {
+		game.info("Game started", "fetchedGame", gameState.Number)
+		game.notifyObservers(func(observer LifecycleObserver) { observer.GameStarted(gameState) })
+	}
+
+	// when we join mid game, the moves so far aren't news
+	if !firstSeen {
+		seenMoves := len(previous.Moves)
+		if newGame || seenMoves > len(gameState.Moves) {
+			seenMoves = 0
+		}
+		for _, move := range gameState.Moves[seenMoves:] {
+			if move.Team != game.ourTeamId {
+				move := move
+				game.notifyObservers(func(observer LifecycleObserver) { observer.OpponentMoveApplied(gameState, move) })
+			}
+		}
+	}
+
+	if (newGame || previous.Turn != gameState.Turn) && gameState.WinningTeam == -1 {
+		game.notifyObservers(func(observer LifecycleObserver) { observer.TurnStarted(gameState) })
+	}
+
+}
+
+func (game *Game) thinkerTimeoutFor(gameState GameState) time.Duration {
+	if game.thinkerTimeout > 0 {
+		return game.thinkerTimeout
+	}
+	return time.Duration(gameState.MoveInterval) * time.Second
+}
+
+// Start a timer that fires ThinkerTimedOut unless stopped in time, or nil
+// if there is no timeout
+func (game *Game) startThinkerTimer(gameState GameState) *time.Timer {
+	timeout := game.thinkerTimeoutFor(gameState)
+	if timeout <= 0 {
+		return nil
+	}
+	return time.AfterFunc(timeout, func() {
+		game.warn("Thinker still running", "timeout", timeout)
+		game.notifyObservers(func(observer LifecycleObserver) { observer.ThinkerTimedOut(gameState, timeout) })
+	})
+}
+
+// Called from the changes feed callback
+func (game *Game) feedActivity() {
+	game.feedMutex.Lock()
+	now := time.Now()
+	lost := game.connectionLost
+	downtime := now.Sub(game.lastFeedActivity)
+	game.lastFeedActivity = now
+	game.connectionLost = false
+	game.feedMutex.Unlock()
+
+	if lost {
+		game.info("Changes feed is back", "downtime", downtime)
+		game.metrics.FeedReconnect()
+		game.notifyObservers(func(observer LifecycleObserver) { observer.ConnectionRestored(downtime) })
+	}
+}
+
+func (game *Game) feedTimeoutOrDefault() time.Duration {
+	if game.feedTimeout <= 0 {
+		return DEFAULT_FEED_TIMEOUT
+	}
+	return game.feedTimeout
+}
+
+// The options for the changes feed.  A longpoll request that sees no
+// changes ends with an empty result after a quarter of the feed timeout,
+// which reaches the callback and counts as activity.  The heartbeat keeps
+// the connection open in between.
+func (game *Game) feedOptions(since interface{}) Changes {
+	options := Changes{"since": since}
+	if game.feedType == LONGPOLL {
+		pollTimeout := game.feedTimeoutOrDefault() / 4
+		options["feed"] = "longpoll"
+		options["timeout"] = int(pollTimeout / time.Millisecond)
+		options["heartbeat"] = int(pollTimeout / 2 / time.Millisecond)
+	}
+	return options
+}
+
+// Fire ConnectionLost when the feed has been quiet for too long.  Returns
+// true if it did.
+func (game *Game) checkFeed(now time.Time) bool {
+	timeout := game.feedTimeoutOrDefault()
+
+	game.feedMutex.Lock()
+	lastActivity := game.lastFeedActivity
+	lost := !game.connectionLost && !lastActivity.IsZero() && now.Sub(lastActivity) > timeout
+	if lost {
+		game.connectionLost = true
+	}
+	game.feedMutex.Unlock()
+
+	if lost {
+		game.warn("Changes feed has gone quiet", "lastActivity", lastActivity)
+		game.notifyObservers(func(observer LifecycleObserver) { observer.ConnectionLost(lastActivity) })
+	}
+	return lost
+}
+
+// Keep an eye on the changes feed until stop is closed
+func (game *Game) watchFeed(stop <-chan bool) {
+	interval := game.feedTimeoutOrDefault() / 4
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			game.checkFeed(now)
+		}
+	}
+}
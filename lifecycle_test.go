@@ -0,0 +1,153 @@
+package checkersbot
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+type recordingObserver struct {
+	BaseLifecycleObserver
+	mutex  sync.Mutex
+	events []string
+}
+
+func (r *recordingObserver) record(format string, args ...interface{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingObserver) Events() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string{}, r.events...)
+}
+
+func (r *recordingObserver) GameStarted(gameState GameState) {
+	r.record("started %v", gameState.Number)
+}
+
+func (r *recordingObserver) TurnStarted(gameState GameState) {
+	r.record("turn %v", gameState.Turn)
+}
+
+func (r *recordingObserver) OpponentMoveApplied(gameState GameState, move MoveHistory) {
+	r.record("opponent %v", PDNMoveString(move))
+}
+
+func (r *recordingObserver) ThinkerTimedOut(gameState GameState, timeout time.Duration) {
+	r.record("timeout")
+}
+
+func (r *recordingObserver) ConnectionLost(lastActivity time.Time) {
+	r.record("lost")
+}
+
+func (r *recordingObserver) ConnectionRestored(downtime time.Duration) {
+	r.record("restored")
+}
+
+// A thinker that observes too
+type observingThinker struct {
+	recordingObserver
+}
+
+func (o *observingThinker) Think(gameState GameState) (validMove ValidMove, ok bool) {
+	return
+}
+
+func TestNotifyGameStateEvents(t *testing.T) {
+
+	observer := &recordingObserver{}
+	game := NewGame(RED_TEAM, nil)
+	game.AddLifecycleObserver(observer)
+
+	gameState, err := NewGameStateFromHistory([]MoveHistory{
+		{Piece: -1, Team: RED_TEAM, Locations: []int{11, 15}},
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{22, 18}},
+	})
+	assert.True(t, err == nil)
+	gameState.Number = 7
+	gameState.Rev = "1-a"
+
+	// joining mid game, the moves so far aren't reported
+	game.notifyGameStateEvents(game.gameState, gameState)
+	assert.DeepEquals(t, observer.Events(), []string{"started 7", "turn 3"})
+
+	// our move and the opponent's reply
+	next, err := NewReferee(AMERICAN_CHECKERS).ApplyMove(gameState, MoveHistory{Piece: -1, Team: RED_TEAM, Locations: []int{15, 22}})
+	assert.True(t, err == nil)
+	next, err = NewReferee(AMERICAN_CHECKERS).ApplyMove(next, MoveHistory{Piece: -1, Team: BLUE_TEAM, Locations: []int{25, 18}})
+	assert.True(t, err == nil)
+	next.Rev = "2-b"
+	game.notifyGameStateEvents(gameState, next)
+	assert.DeepEquals(t, observer.Events(), []string{"started 7", "turn 3", "opponent 25x18", "turn 5"})
+
+	// same revision again, nothing new
+	game.notifyGameStateEvents(next, next)
+	assert.Equals(t, len(observer.Events()), 4)
+
+}
+
+func TestLifecycleObserverThinker(t *testing.T) {
+
+	thinker := &observingThinker{}
+	added := &recordingObserver{}
+	game := NewGame(RED_TEAM, thinker)
+	game.AddLifecycleObserver(added)
+	assert.Equals(t, len(game.lifecycleObservers()), 2)
+
+	game.SetThinkerTimeout(time.Millisecond)
+	timer := game.startThinkerTimer(GameState{})
+	assert.True(t, timer != nil)
+	time.Sleep(50 * time.Millisecond)
+	assert.DeepEquals(t, thinker.Events(), []string{"timeout"})
+	assert.DeepEquals(t, added.Events(), []string{"timeout"})
+
+	// no timeout and no moveInterval
+	game.SetThinkerTimeout(0)
+	assert.True(t, game.startThinkerTimer(GameState{}) == nil)
+
+}
+
+func TestConnectionLost(t *testing.T) {
+
+	observer := &recordingObserver{}
+	game := NewGame(RED_TEAM, nil)
+	game.AddLifecycleObserver(observer)
+	game.SetFeedTimeout(time.Minute)
+
+	// nothing heard yet
+	assert.False(t, game.checkFeed(time.Now()))
+
+	game.feedActivity()
+	assert.False(t, game.checkFeed(time.Now()))
+	assert.True(t, game.checkFeed(time.Now().Add(2*time.Minute)))
+	assert.False(t, game.checkFeed(time.Now().Add(3*time.Minute)))
+	game.feedActivity()
+	assert.DeepEquals(t, observer.Events(), []string{"lost", "restored"})
+
+}
+
+func TestFeedOptions(t *testing.T) {
+
+	game := NewGame(RED_TEAM, nil)
+	game.SetFeedType(LONGPOLL)
+	game.SetFeedTimeout(time.Minute)
+
+	// the longpoll ends in time to count as activity
+	options := game.feedOptions("12")
+	assert.Equals(t, options["since"], "12")
+	assert.Equals(t, options["feed"], "longpoll")
+	assert.Equals(t, options["timeout"], 15000)
+	assert.Equals(t, options["heartbeat"], 7500)
+
+	game.SetFeedType(NORMAL)
+	options = game.feedOptions("12")
+	assert.Equals(t, len(options), 1)
+
+}
@@ -0,0 +1,138 @@
+package checkersbot
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/couchbaselabs/logg"
+)
+
+// Where a Game sends its log records.  Arguments after the message are
+// alternating keys and values, as with log/slog, and a *slog.Logger can
+// be used as is.  Every record from a Game carries team, user, game, turn
+// and rev fields ahead of its own.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// A Logger writing one JSON object per record, leaving out records below
+// the level
+func NewJSONLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// The default Logger, which writes key=value lines through logg under the
+// CHECKERSBOT key like the rest of the package
+type LoggLogger struct {
+	Level slog.Level
+}
+
+func NewLoggLogger(level slog.Level) *LoggLogger {
+	return &LoggLogger{Level: level}
+}
+
+func (l *LoggLogger) Debug(msg string, args ...interface{}) {
+	l.log(slog.LevelDebug, msg, args)
+}
+
+func (l *LoggLogger) Info(msg string, args ...interface{}) {
+	l.log(slog.LevelInfo, msg, args)
+}
+
+func (l *LoggLogger) Warn(msg string, args ...interface{}) {
+	l.log(slog.LevelWarn, msg, args)
+}
+
+func (l *LoggLogger) Error(msg string, args ...interface{}) {
+	l.log(slog.LevelError, msg, args)
+}
+
+func (l *LoggLogger) log(level slog.Level, msg string, args []interface{}) {
+	if level < l.Level {
+		return
+	}
+	logg.LogTo("CHECKERSBOT", "%v %v%v", level, msg, formatLogFields(args))
+}
+
+// " key=value key=value", with values that have spaces quoted and an odd
+// one out under !BADKEY, as slog does
+func formatLogFields(args []interface{}) string {
+	buf := strings.Builder{}
+	for i := 0; i < len(args); i += 2 {
+		key, value := "!BADKEY", args[i]
+		if i+1 < len(args) {
+			key, value = fmt.Sprintf("%v", args[i]), args[i+1]
+		}
+		text := fmt.Sprintf("%v", value)
+		if strings.ContainsAny(text, " \n\t\"=") || text == "" {
+			text = fmt.Sprintf("%q", text)
+		}
+		fmt.Fprintf(&buf, " %v=%v", key, text)
+	}
+	return buf.String()
+}
+
+func (game *Game) SetLogger(logger Logger) {
+	game.logger = logger
+}
+
+// For Games that weren't made with NewGame or had their Logger unset
+var defaultLogger Logger = newDefaultLogger()
+
+func newDefaultLogger() Logger {
+	return NewLoggLogger(slog.LevelInfo)
+}
+
+// The game's Logger, by default a LoggLogger at Info level
+func (game *Game) Logger() Logger {
+	if game.logger == nil {
+		return defaultLogger
+	}
+	return game.logger
+}
+
+// The fields every record from the game carries
+func (game *Game) logFields(args []interface{}) []interface{} {
+	game.statusMutex.Lock()
+	fields := []interface{}{
+		"team", game.ourTeamId.String(),
+		"user", game.statusUserId,
+		"game", game.statusGameNumber,
+		"turn", game.statusTurn,
+		"rev", game.statusRev,
+	}
+	game.statusMutex.Unlock()
+	return append(fields, args...)
+}
+
+func (game *Game) debug(msg string, args ...interface{}) {
+	game.Logger().Debug(msg, game.logFields(args)...)
+}
+
+func (game *Game) info(msg string, args ...interface{}) {
+	game.Logger().Info(msg, game.logFields(args)...)
+}
+
+func (game *Game) warn(msg string, args ...interface{}) {
+	game.Logger().Warn(msg, game.logFields(args)...)
+}
+
+func (game *Game) logError(msg string, err error, args ...interface{}) {
+	game.Logger().Error(msg, game.logFields(append([]interface{}{"err", err}, args...))...)
+}
+
+// A board for a log record, only drawn if the record gets written
+type loggedBoard GameState
+
+func (board loggedBoard) String() string {
+	return GameState(board).RenderString()
+}
+
+func (board loggedBoard) LogValue() slog.Value {
+	return slog.StringValue(board.String())
+}
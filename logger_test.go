@@ -0,0 +1,75 @@
+package checkersbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestJSONLoggerGameFields(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	game := NewGame(BLUE_TEAM, nil)
+	game.SetLogger(NewJSONLogger(buf, slog.LevelInfo))
+	game.recordUser(User{Id: "user:1234"})
+	game.recordGameState(GameState{Number: 7, Turn: 4, Rev: "12-abc"})
+
+	game.info("Game started", "fetchedGame", 7)
+	game.debug("Calling thinker")
+	game.logError("Error sending vote", errors.New("409 conflict"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equals(t, len(lines), 2)
+
+	record := map[string]interface{}{}
+	assert.True(t, json.Unmarshal([]byte(lines[0]), &record) == nil)
+	assert.Equals(t, record["level"], "INFO")
+	assert.Equals(t, record["msg"], "Game started")
+	assert.Equals(t, record["team"], "BLUE")
+	assert.Equals(t, record["user"], "user:1234")
+	assert.Equals(t, record["game"], float64(7))
+	assert.Equals(t, record["turn"], float64(4))
+	assert.Equals(t, record["rev"], "12-abc")
+	assert.Equals(t, record["fetchedGame"], float64(7))
+
+	record = map[string]interface{}{}
+	assert.True(t, json.Unmarshal([]byte(lines[1]), &record) == nil)
+	assert.Equals(t, record["level"], "ERROR")
+	assert.Equals(t, record["err"], "409 conflict")
+
+}
+
+func TestDefaultLoggerLevel(t *testing.T) {
+	logger, ok := NewGame(RED_TEAM, nil).Logger().(*LoggLogger)
+	assert.True(t, ok)
+	assert.Equals(t, logger.Level, slog.LevelInfo)
+}
+
+func TestLoggedBoard(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("B:W18:B14")
+	buf := &bytes.Buffer{}
+	game := NewGame(RED_TEAM, nil)
+	game.SetLogger(NewJSONLogger(buf, slog.LevelDebug))
+	game.debug("Game state", "board", loggedBoard(gameState))
+
+	record := map[string]interface{}{}
+	assert.True(t, json.Unmarshal(buf.Bytes(), &record) == nil)
+	assert.Equals(t, record["board"], gameState.RenderString())
+	assert.Equals(t, formatLogFields([]interface{}{"board", loggedBoard(gameState)}), formatLogFields([]interface{}{"board", gameState.RenderString()}))
+
+}
+
+func TestFormatLogFields(t *testing.T) {
+
+	assert.Equals(t, formatLogFields(nil), "")
+	assert.Equals(t, formatLogFields([]interface{}{"team", "RED", "turn", 3}), " team=RED turn=3")
+	assert.Equals(t, formatLogFields([]interface{}{"board", "a b", "rev", ""}), ` board="a b" rev=""`)
+	assert.Equals(t, formatLogFields([]interface{}{"team", "RED", "stray"}), " team=RED !BADKEY=stray")
+
+}
@@ -17,3 +17,10 @@ func randomInRange(min, max float64) float64 {
 
 	return rand.Float64()*(max-min) + min
 }
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
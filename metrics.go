@@ -0,0 +1,255 @@
+package checkersbot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Histogram buckets, in seconds
+var (
+	THINK_SECONDS_BUCKETS = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	VOTE_SECONDS_BUCKETS  = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+)
+
+// Counters and timings for one Game, exposed in the Prometheus text
+// format.  Every series is labelled with our team.  The methods do
+// nothing on a nil *Metrics, so the Game can call them whether or not
+// metrics are enabled.
+type Metrics struct {
+	team  TeamType
+	mutex sync.Mutex
+
+	turnsPlayed     uint64
+	lastTurnPlayed  string
+	thinkSeconds    *histogram
+	votePostSeconds *histogram
+	voteErrors      uint64
+	casRetries      uint64
+	feedReconnects  uint64
+	sinceReceived   int64
+	sinceHandled    int64
+	votesAgreed     uint64
+	votesOverruled  uint64
+	gamesWon        uint64
+	gamesLost       uint64
+	lastGameCounted int
+}
+
+func NewMetrics(team TeamType) *Metrics {
+	return &Metrics{
+		team:            team,
+		thinkSeconds:    newHistogram(THINK_SECONDS_BUCKETS),
+		votePostSeconds: newHistogram(VOTE_SECONDS_BUCKETS),
+		lastGameCounted: -1,
+	}
+}
+
+// Counts each of our turns once, however many times the game doc changes
+// during it
+func (m *Metrics) TurnPlayed(gameNumber, turn int) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	key := fmt.Sprintf("%v/%v", gameNumber, turn)
+	if key != m.lastTurnPlayed {
+		m.lastTurnPlayed = key
+		m.turnsPlayed++
+	}
+}
+
+func (m *Metrics) ObserveThink(duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.thinkSeconds.observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveVotePost(duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.votePostSeconds.observe(duration.Seconds())
+	if err != nil {
+		m.voteErrors++
+	}
+}
+
+func (m *Metrics) CASRetry() {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.casRetries++
+}
+
+func (m *Metrics) FeedReconnect() {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.feedReconnects++
+}
+
+// The since lag is how far the changes the game loop has handled trail
+// the ones the feed has received, in sequence numbers.  The feed reports
+// what it received, the game loop what it handled.
+func (m *Metrics) SinceReceived(since string) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sinceReceived = sinceSequence(since)
+}
+
+func (m *Metrics) SinceHandled(since string) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sinceHandled = sinceSequence(since)
+}
+
+// Counts a turn we voted on by whether our team played our vote
+func (m *Metrics) VoteOutcome(agreed bool) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if agreed {
+		m.votesAgreed++
+	} else {
+		m.votesOverruled++
+	}
+}
+
+// Counts each finished game once
+func (m *Metrics) GameFinished(gameNumber int, winningTeam TeamType) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if gameNumber == m.lastGameCounted {
+		return
+	}
+	m.lastGameCounted = gameNumber
+	if winningTeam == m.team {
+		m.gamesWon++
+	} else {
+		m.gamesLost++
+	}
+}
+
+// Write the metrics in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (n int64, err error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	buf := &bytes.Buffer{}
+	labels := fmt.Sprintf(`team="%v"`, m.team)
+
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(buf, "# HELP %v %v\n# TYPE %v %v\n", name, help, name, kind)
+	}
+	sample := func(name, labels string, value interface{}) {
+		fmt.Fprintf(buf, "%v{%v} %v\n", name, labels, value)
+	}
+
+	metric("checkersbot_turns_played_total", "counter", "Turns where it was our team's move.")
+	sample("checkersbot_turns_played_total", labels, m.turnsPlayed)
+
+	metric("checkersbot_think_seconds", "histogram", "Time the thinker took to pick a move.")
+	m.thinkSeconds.write(buf, "checkersbot_think_seconds", labels)
+
+	metric("checkersbot_vote_post_seconds", "histogram", "Time taken to post a vote to the server.")
+	m.votePostSeconds.write(buf, "checkersbot_vote_post_seconds", labels)
+
+	metric("checkersbot_vote_errors_total", "counter", "Votes the server didn't accept.")
+	sample("checkersbot_vote_errors_total", labels, m.voteErrors)
+
+	metric("checkersbot_vote_outcomes_total", "counter", "Turns we voted on, by whether our team played the move we voted for.")
+	sample("checkersbot_vote_outcomes_total", labels+`,result="agreed"`, m.votesAgreed)
+	sample("checkersbot_vote_outcomes_total", labels+`,result="overruled"`, m.votesOverruled)
+
+	metric("checkersbot_user_update_cas_retries_total", "counter", "Conflicts retried while updating the user's game number.")
+	sample("checkersbot_user_update_cas_retries_total", labels, m.casRetries)
+
+	metric("checkersbot_feed_reconnects_total", "counter", "Times the changes feed came back after going quiet.")
+	sample("checkersbot_feed_reconnects_total", labels, m.feedReconnects)
+
+	metric("checkersbot_feed_since_lag", "gauge", "Sequence numbers received by the changes feed but not yet handled.")
+	sample("checkersbot_feed_since_lag", labels, m.sinceReceived-m.sinceHandled)
+
+	metric("checkersbot_games_total", "counter", "Finished games by result.")
+	sample("checkersbot_games_total", labels+`,result="won"`, m.gamesWon)
+	sample("checkersbot_games_total", labels+`,result="lost"`, m.gamesLost)
+
+	metric("checkersbot_goroutines", "gauge", "Goroutines in the bot process.")
+	sample("checkersbot_goroutines", labels, runtime.NumGoroutine())
+
+	return buf.WriteTo(w)
+
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// The sequence number in a since value, eg, 3641 for "*:3641"
+func sinceSequence(since string) int64 {
+	if i := strings.LastIndex(since, ":"); i >= 0 {
+		since = since[i+1:]
+	}
+	sequence, _ := strconv.ParseInt(since, 10, 64)
+	return sequence
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (h *histogram) write(w io.Writer, name, labels string) {
+	for i, bound := range h.buckets {
+		fmt.Fprintf(w, "%v_bucket{%v,le=\"%v\"} %v\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+	}
+	fmt.Fprintf(w, "%v_bucket{%v,le=\"+Inf\"} %v\n", name, labels, h.count)
+	fmt.Fprintf(w, "%v_sum{%v} %v\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%v_count{%v} %v\n", name, labels, h.count)
+}
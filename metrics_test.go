@@ -0,0 +1,80 @@
+package checkersbot
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestMetrics(t *testing.T) {
+
+	metrics := NewMetrics(BLUE_TEAM)
+	metrics.TurnPlayed(7, 2)
+	metrics.TurnPlayed(7, 2)
+	metrics.TurnPlayed(7, 4)
+	metrics.ObserveThink(300 * time.Millisecond)
+	metrics.ObserveThink(20 * time.Second)
+	metrics.ObserveVotePost(20*time.Millisecond, nil)
+	metrics.ObserveVotePost(20*time.Millisecond, errors.New("conflict"))
+	metrics.CASRetry()
+	metrics.FeedReconnect()
+	metrics.SinceReceived("*:3650")
+	metrics.SinceHandled("*:3641")
+	metrics.GameFinished(7, BLUE_TEAM)
+	metrics.GameFinished(7, BLUE_TEAM)
+	metrics.GameFinished(8, RED_TEAM)
+
+	buf := &strings.Builder{}
+	_, err := metrics.WriteTo(buf)
+	assert.True(t, err == nil)
+	text := buf.String()
+
+	for _, line := range []string{
+		"# TYPE checkersbot_turns_played_total counter",
+		`checkersbot_turns_played_total{team="BLUE"} 2`,
+		"# TYPE checkersbot_think_seconds histogram",
+		`checkersbot_think_seconds_bucket{team="BLUE",le="0.25"} 0`,
+		`checkersbot_think_seconds_bucket{team="BLUE",le="0.5"} 1`,
+		`checkersbot_think_seconds_bucket{team="BLUE",le="30"} 2`,
+		`checkersbot_think_seconds_bucket{team="BLUE",le="+Inf"} 2`,
+		`checkersbot_think_seconds_sum{team="BLUE"} 20.3`,
+		`checkersbot_think_seconds_count{team="BLUE"} 2`,
+		`checkersbot_vote_post_seconds_count{team="BLUE"} 2`,
+		`checkersbot_vote_errors_total{team="BLUE"} 1`,
+		`checkersbot_user_update_cas_retries_total{team="BLUE"} 1`,
+		`checkersbot_feed_reconnects_total{team="BLUE"} 1`,
+		`checkersbot_feed_since_lag{team="BLUE"} 9`,
+		`checkersbot_games_total{team="BLUE",result="won"} 1`,
+		`checkersbot_games_total{team="BLUE",result="lost"} 1`,
+		`checkersbot_goroutines{team="BLUE"} `,
+	} {
+		assert.True(t, strings.Contains(text, line))
+	}
+
+	// a nil *Metrics ignores everything
+	var disabled *Metrics
+	disabled.TurnPlayed(1, 1)
+	disabled.ObserveThink(time.Second)
+
+}
+
+func TestMonitoringHandlerMetrics(t *testing.T) {
+
+	game := NewGame(RED_TEAM, nil)
+	server := httptest.NewServer(game.MonitoringHandler())
+	defer server.Close()
+
+	game.Metrics().TurnPlayed(1, 1)
+	response, err := server.Client().Get(server.URL + "/metrics")
+	assert.True(t, err == nil)
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+	assert.True(t, strings.HasPrefix(response.Header.Get("Content-Type"), "text/plain"))
+	assert.True(t, strings.Contains(string(body), `checkersbot_turns_played_total{team="RED"} 1`))
+
+}
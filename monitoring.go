@@ -0,0 +1,50 @@
+package checkersbot
+
+import (
+	"net"
+	"net/http"
+)
+
+// Turn on metrics collection for the game.  Call it before GameLoop.
+func (game *Game) EnableMetrics() *Metrics {
+	if game.metrics == nil {
+		game.metrics = NewMetrics(game.ourTeamId)
+	}
+	return game.metrics
+}
+
+// Nil unless metrics are enabled
+func (game *Game) Metrics() *Metrics {
+	return game.metrics
+}
+
+// The monitoring endpoints: Prometheus metrics on /metrics, a liveness
+// check on /healthz and a JSON Status on /status
+func (game *Game) MonitoringHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", game.EnableMetrics())
+	mux.HandleFunc("/healthz", game.serveHealthz)
+	mux.HandleFunc("/status", game.serveStatus)
+	return mux
+}
+
+// Serve the monitoring endpoints on addr, eg, ":9100", in the background.
+// Returns once the listener is up, with the server's Addr set to where
+// it's listening.  Call it before GameLoop.
+func (game *Game) StartMonitoring(addr string) (*http.Server, error) {
+
+	handler := game.MonitoringHandler()
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{Addr: listener.Addr().String(), Handler: handler}
+	game.info("Serving monitoring endpoints", "addr", server.Addr)
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			game.logError("Monitoring server stopped", err)
+		}
+	}()
+	return server, nil
+
+}
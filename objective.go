@@ -0,0 +1,79 @@
+package checkersbot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// What a team is playing for
+type Objective int
+
+const (
+	// The usual game: a team that loses all its pieces or can't move
+	// loses
+	STANDARD_OBJECTIVE = Objective(iota)
+
+	// Giveaway, aka anti-checkers: a team that loses all its pieces or
+	// can't move wins.  Captures are still compulsory.
+	GIVEAWAY_OBJECTIVE
+)
+
+func (o Objective) String() string {
+	switch o {
+	case GIVEAWAY_OBJECTIVE:
+		return "giveaway"
+	default:
+		return "standard"
+	}
+}
+
+func ParseObjective(name string) (Objective, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "standard", "normal":
+		return STANDARD_OBJECTIVE, nil
+	case "giveaway", "anti", "misere":
+		return GIVEAWAY_OBJECTIVE, nil
+	}
+	return STANDARD_OBJECTIVE, fmt.Errorf("unknown objective %q, expected standard or giveaway", name)
+}
+
+// 1 for the standard game and -1 for giveaway.  Multiply an evaluation
+// written for the standard game, eg, material count, by this to get one
+// for the objective in force.
+func (o Objective) Sign() int {
+	if o == GIVEAWAY_OBJECTIVE {
+		return -1
+	}
+	return 1
+}
+
+// The team that wins when the team to move has no legal move left
+func (o Objective) WinnerWhenStuck(activeTeam TeamType) TeamType {
+	if o == GIVEAWAY_OBJECTIVE {
+		return activeTeam
+	}
+	return activeTeam.Opponent()
+}
+
+func (game *Game) SetObjective(objective Objective) {
+	game.objective = objective
+}
+
+func (game *Game) Objective() Objective {
+	return game.objective
+}
+
+// The server only knows the standard game.  When we are playing giveaway,
+// mark the game state with the objective so thinkers can see it, and turn
+// the server's winner around: the team it says won is the one that still
+// has pieces and moves.
+func (game *Game) withObjective(gameState GameState) GameState {
+	if game.objective == STANDARD_OBJECTIVE || gameState.Objective == game.objective {
+		return gameState
+	}
+	if gameState.WinningTeam != -1 {
+		gameState.WinningTeam = gameState.WinningTeam.Opponent()
+	}
+	gameState.Objective = game.objective
+	return gameState
+}
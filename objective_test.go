@@ -0,0 +1,58 @@
+package checkersbot
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestGiveawayReferee(t *testing.T) {
+
+	gameState := PopulateValidMoves(MustGameStateFromFEN("W:W18:B14"))
+	move := MoveHistory{Piece: -1, Team: BLUE_TEAM, Locations: []int{18, 9}}
+
+	next, err := NewReferee(AMERICAN_CHECKERS).ApplyMove(gameState, move)
+	assert.True(t, err == nil)
+	assert.Equals(t, next.WinningTeam, BLUE_TEAM)
+	assert.Equals(t, next.Objective, STANDARD_OBJECTIVE)
+
+	// red has nothing left, which wins the giveaway game
+	next, err = NewGiveawayReferee(AMERICAN_CHECKERS).ApplyMove(gameState, move)
+	assert.True(t, err == nil)
+	assert.Equals(t, next.WinningTeam, RED_TEAM)
+	assert.Equals(t, next.Objective, GIVEAWAY_OBJECTIVE)
+
+	initial := NewGiveawayReferee(AMERICAN_CHECKERS).InitialGameState()
+	assert.Equals(t, initial.Objective, GIVEAWAY_OBJECTIVE)
+	assert.Equals(t, initial.Objective.Sign(), -1)
+	assert.Equals(t, STANDARD_OBJECTIVE.Sign(), 1)
+
+}
+
+func TestGiveawayFinished(t *testing.T) {
+
+	gameState := MustGameStateFromFEN(INITIAL_FEN)
+	game := NewGame(RED_TEAM, nil)
+	game.SetObjective(GIVEAWAY_OBJECTIVE)
+	assert.Equals(t, game.Objective(), GIVEAWAY_OBJECTIVE)
+	assert.False(t, game.finished(gameState))
+
+	// the server says blue won, so red ran out of pieces or moves
+	gameState.WinningTeam = BLUE_TEAM
+	assert.True(t, game.finished(gameState))
+	marked := game.withObjective(gameState)
+	assert.Equals(t, marked.WinningTeam, RED_TEAM)
+	assert.Equals(t, marked.Objective, GIVEAWAY_OBJECTIVE)
+
+	// already marked, or from a server that plays giveaway itself
+	assert.Equals(t, game.withObjective(marked).WinningTeam, RED_TEAM)
+
+}
+
+func TestParseObjective(t *testing.T) {
+	objective, err := ParseObjective("Giveaway")
+	assert.True(t, err == nil)
+	assert.Equals(t, objective, GIVEAWAY_OBJECTIVE)
+	_, err = ParseObjective("draw")
+	assert.True(t, err != nil)
+}
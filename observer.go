@@ -3,3 +3,16 @@ package checkersbot
 type Observer interface {
 	GameFinished(gameState GameState) (shouldQuit bool)
 }
+
+// Thinkers and lifecycle observers that implement this get the
+// GameReport for each finished game, before GameFinished is called.
+type GameReportObserver interface {
+	GameReported(report GameReport)
+}
+
+// Thinkers and lifecycle observers that implement this hear, for each
+// turn we voted on, whether our team played the move we voted for, along
+// with the running InfluenceStats.
+type VoteOutcomeObserver interface {
+	VoteOutcome(outcome VoteOutcome, influence InfluenceStats)
+}
@@ -0,0 +1,311 @@
+package checkersbot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PDN_BLACK_WINS = "0-1"
+	PDN_WHITE_WINS = "1-0"
+	PDN_DRAW       = "1/2-1/2"
+	PDN_UNFINISHED = "*"
+
+	// Tags that aren't part of the PDN standard
+	PDN_TAG_GAME_NUMBER = "GameNumber"
+	PDN_TAG_START_TIME  = "StartTime"
+)
+
+// The standard tags, written first and in this order
+var pdnSevenTagRoster = []string{"Event", "Site", "Date", "Round", "Black", "White", "Result"}
+
+// A game in Portable Draughts Notation.  PDN uses the same 1-32 square
+// numbers as the game doc.  The RED team starts on squares 1-12 and moves
+// first, which makes it Black in PDN terms, and BLUE is White.
+type PDNGame struct {
+	Tags  map[string]string
+	Moves []MoveHistory
+}
+
+// Build a PDN game from the moves played so far
+func NewPDNGame(gameState GameState) PDNGame {
+
+	pdnGame := PDNGame{Tags: map[string]string{}}
+	pdnGame.Tags["Event"] = "Couchbase Checkers"
+	pdnGame.Tags["Site"] = "?"
+	pdnGame.Tags["Round"] = strconv.Itoa(gameState.Number)
+	pdnGame.Tags["Black"] = RED_TEAM.String()
+	pdnGame.Tags["White"] = BLUE_TEAM.String()
+	pdnGame.Tags["GameType"] = "21"
+	pdnGame.Tags[PDN_TAG_GAME_NUMBER] = strconv.Itoa(gameState.Number)
+
+	pdnGame.Tags["Date"] = "????.??.??"
+	if !gameState.StartTime.IsZero() {
+		pdnGame.Tags["Date"] = gameState.StartTime.UTC().Format("2006.01.02")
+		pdnGame.Tags[PDN_TAG_START_TIME] = gameState.StartTime.UTC().Format(time.RFC3339)
+	}
+
+	switch gameState.WinningTeam {
+	case RED_TEAM:
+		pdnGame.Tags["Result"] = PDN_BLACK_WINS
+	case BLUE_TEAM:
+		pdnGame.Tags["Result"] = PDN_WHITE_WINS
+	default:
+		pdnGame.Tags["Result"] = PDN_UNFINISHED
+	}
+
+	pdnGame.Moves = gameState.Moves
+	return pdnGame
+
+}
+
+// The game number from the GameNumber tag, falling back to Round
+func (pdnGame PDNGame) GameNumber() int {
+	for _, tag := range []string{PDN_TAG_GAME_NUMBER, "Round"} {
+		if number, err := strconv.Atoi(pdnGame.Tags[tag]); err == nil {
+			return number
+		}
+	}
+	return 0
+}
+
+// The winning team according to the Result tag, or -1 if there isn't one
+func (pdnGame PDNGame) WinningTeam() TeamType {
+	switch pdnGame.Tags["Result"] {
+	case PDN_BLACK_WINS:
+		return RED_TEAM
+	case PDN_WHITE_WINS:
+		return BLUE_TEAM
+	default:
+		return -1
+	}
+}
+
+func (pdnGame PDNGame) StartTime() time.Time {
+	startTime, _ := time.Parse(time.RFC3339, pdnGame.Tags[PDN_TAG_START_TIME])
+	return startTime
+}
+
+func (pdnGame PDNGame) String() string {
+	buf := bytes.Buffer{}
+	pdnGame.WriteTo(&buf)
+	return buf.String()
+}
+
+func (pdnGame PDNGame) WriteTo(w io.Writer) (n int64, err error) {
+
+	buf := bytes.Buffer{}
+
+	written := map[string]bool{}
+	for _, tag := range pdnSevenTagRoster {
+		value, ok := pdnGame.Tags[tag]
+		if !ok {
+			value = "?"
+			if tag == "Result" {
+				value = PDN_UNFINISHED
+			}
+		}
+		writePDNTag(&buf, tag, value)
+		written[tag] = true
+	}
+	otherTags := []string{}
+	for tag := range pdnGame.Tags {
+		if !written[tag] {
+			otherTags = append(otherTags, tag)
+		}
+	}
+	sort.Strings(otherTags)
+	for _, tag := range otherTags {
+		writePDNTag(&buf, tag, pdnGame.Tags[tag])
+	}
+	buf.WriteString("\n")
+
+	tokens := []string{}
+	moveNumber := 0
+	for i, move := range pdnGame.Moves {
+		if move.Team == RED_TEAM {
+			moveNumber++
+			tokens = append(tokens, fmt.Sprintf("%d.", moveNumber))
+		} else if i == 0 {
+			moveNumber++
+			tokens = append(tokens, fmt.Sprintf("%d...", moveNumber))
+		}
+		tokens = append(tokens, PDNMoveString(move))
+	}
+	result := pdnGame.Tags["Result"]
+	if result == "" {
+		result = PDN_UNFINISHED
+	}
+	tokens = append(tokens, result)
+
+	// wrap the move text at 80 columns
+	lineLength := 0
+	for i, token := range tokens {
+		if i > 0 && lineLength+1+len(token) > 80 {
+			buf.WriteString("\n")
+			lineLength = 0
+		} else if i > 0 {
+			buf.WriteString(" ")
+			lineLength++
+		}
+		buf.WriteString(token)
+		lineLength += len(token)
+	}
+	buf.WriteString("\n\n")
+
+	written64, err := w.Write(buf.Bytes())
+	return int64(written64), err
+
+}
+
+// Format a played move, using x for jumps
+func PDNMoveString(move MoveHistory) string {
+	if len(move.Locations) == 0 {
+		return ""
+	}
+	return MoveNotation(move.Locations[0], move.Locations[1:], isJumpPath(move.Locations))
+}
+
+// Parse every game in a PDN file.  PDN doesn't say which piece moved, so
+// the Piece of each parsed MoveHistory is -1, and the turns are counted
+// from 1.
+func ParsePDN(r io.Reader) (pdnGames []PDNGame, err error) {
+
+	scanner := bufio.NewScanner(r)
+	current := PDNGame{Tags: map[string]string{}}
+	inMoves := false
+	inComment := false
+	nextTeam := RED_TEAM
+	lineNumber := 0
+
+	finishGame := func() {
+		if len(current.Tags) > 0 || len(current.Moves) > 0 {
+			pdnGames = append(pdnGames, current)
+		}
+		current = PDNGame{Tags: map[string]string{}}
+		inMoves = false
+		nextTeam = RED_TEAM
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if !inComment && strings.HasPrefix(line, "[") {
+			if inMoves {
+				finishGame()
+			}
+			tag, value, tagErr := parsePDNTag(line)
+			if tagErr != nil {
+				return nil, fmt.Errorf("line %v: %v", lineNumber, tagErr)
+			}
+			current.Tags[tag] = value
+			continue
+		}
+
+		for _, token := range strings.Fields(line) {
+			if inComment {
+				if strings.HasSuffix(token, "}") {
+					inComment = false
+				}
+				continue
+			}
+			if strings.HasPrefix(token, "{") {
+				inComment = !strings.HasSuffix(token, "}")
+				continue
+			}
+
+			inMoves = true
+			switch token {
+			case PDN_BLACK_WINS, PDN_WHITE_WINS, PDN_DRAW, PDN_UNFINISHED:
+				if _, ok := current.Tags["Result"]; !ok {
+					current.Tags["Result"] = token
+				}
+				finishGame()
+				continue
+			}
+
+			// move numbers, "1." or "1..." before a white move
+			if index := strings.LastIndex(token, "."); index >= 0 {
+				nextTeam = RED_TEAM
+				if strings.HasSuffix(token[:index+1], "...") {
+					nextTeam = BLUE_TEAM
+				}
+				token = token[index+1:]
+				if token == "" {
+					continue
+				}
+			}
+
+			start, locations, moveErr := ParseMoveNotation(token)
+			if moveErr != nil {
+				return nil, fmt.Errorf("line %v: %v", lineNumber, moveErr)
+			}
+			move := MoveHistory{
+				Piece:     -1,
+				Team:      nextTeam,
+				Turn:      len(current.Moves) + 1,
+				Locations: append([]int{start}, locations...),
+			}
+			current.Moves = append(current.Moves, move)
+			nextTeam = nextTeam.Opponent()
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	finishGame()
+	return pdnGames, nil
+
+}
+
+// Parse a file with exactly one game in it
+func ParsePDNGame(r io.Reader) (pdnGame PDNGame, err error) {
+	pdnGames, err := ParsePDN(r)
+	if err != nil {
+		return
+	}
+	if len(pdnGames) != 1 {
+		return pdnGame, fmt.Errorf("expected one game in PDN, found %v", len(pdnGames))
+	}
+	return pdnGames[0], nil
+}
+
+func parsePDNTag(line string) (tag, value string, err error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", fmt.Errorf("unterminated tag %q", line)
+	}
+	inner := strings.TrimSpace(line[1 : len(line)-1])
+	space := strings.IndexAny(inner, " \t")
+	if space < 0 {
+		return "", "", fmt.Errorf("tag %q has no value", line)
+	}
+	tag = inner[:space]
+	value, err = strconv.Unquote(strings.TrimSpace(inner[space:]))
+	if err != nil {
+		return "", "", fmt.Errorf("tag %q has a badly quoted value", line)
+	}
+	return
+}
+
+func writePDNTag(buf *bytes.Buffer, tag, value string) {
+	fmt.Fprintf(buf, "[%v %v]\n", tag, strconv.Quote(value))
+}
+
+// A path is a jump if it has more than one leg, or its one leg skips a row
+func isJumpPath(locations []int) bool {
+	if len(locations) > 2 {
+		return true
+	}
+	if len(locations) < 2 {
+		return false
+	}
+	rowDelta := GetCoreLocation(locations[1]).Row() - GetCoreLocation(locations[0]).Row()
+	return rowDelta == 2 || rowDelta == -2
+}
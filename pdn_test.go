@@ -0,0 +1,80 @@
+package checkersbot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestPDNRoundTrip(t *testing.T) {
+
+	gameState := GameState{
+		Number:      153563,
+		WinningTeam: BLUE_TEAM,
+		StartTime:   time.Date(2013, 9, 20, 17, 11, 53, 0, time.UTC),
+		Moves: []MoveHistory{
+			{Team: RED_TEAM, Locations: []int{10, 14}},
+			{Team: BLUE_TEAM, Locations: []int{23, 19}},
+			{Team: RED_TEAM, Locations: []int{14, 23}},
+			{Team: BLUE_TEAM, Locations: []int{27, 18, 9}},
+			{Team: RED_TEAM, Locations: []int{6, 10}},
+		},
+	}
+
+	pdnGame := NewPDNGame(gameState)
+	pdn := pdnGame.String()
+	assert.True(t, strings.Contains(pdn, `[Result "1-0"]`))
+	assert.True(t, strings.Contains(pdn, `[Date "2013.09.20"]`))
+	assert.True(t, strings.Contains(pdn, "1. 10-14 23-19 2. 14x23 27x18x9 3. 6-10 1-0"))
+
+	parsed, err := ParsePDNGame(strings.NewReader(pdn))
+	assert.True(t, err == nil)
+	assert.Equals(t, parsed.GameNumber(), 153563)
+	assert.Equals(t, parsed.WinningTeam(), BLUE_TEAM)
+	assert.True(t, parsed.StartTime().Equal(gameState.StartTime))
+	assert.Equals(t, len(parsed.Moves), len(gameState.Moves))
+	for i, move := range parsed.Moves {
+		assert.Equals(t, move.Team, gameState.Moves[i].Team)
+		assert.DeepEquals(t, move.Locations, gameState.Moves[i].Locations)
+	}
+
+	// and back out again, unchanged
+	buf := &bytes.Buffer{}
+	parsed.WriteTo(buf)
+	assert.Equals(t, buf.String(), pdn)
+
+}
+
+func TestParsePDNMultipleGames(t *testing.T) {
+
+	pdn := `[Event "one"]
+[Result "*"]
+
+1. 9-13 {a comment
+spanning lines} 22-18 *
+
+[Event "two"]
+
+1... 22-18 2.11-15 18x11 0-1
+`
+	pdnGames, err := ParsePDN(strings.NewReader(pdn))
+	assert.True(t, err == nil)
+	assert.Equals(t, len(pdnGames), 2)
+
+	assert.Equals(t, pdnGames[0].Tags["Event"], "one")
+	assert.Equals(t, len(pdnGames[0].Moves), 2)
+	assert.Equals(t, pdnGames[0].WinningTeam(), TeamType(-1))
+
+	assert.Equals(t, pdnGames[1].Tags["Result"], "0-1")
+	assert.Equals(t, len(pdnGames[1].Moves), 3)
+	assert.Equals(t, pdnGames[1].Moves[0].Team, BLUE_TEAM)
+	assert.Equals(t, pdnGames[1].Moves[1].Team, RED_TEAM)
+	assert.DeepEquals(t, pdnGames[1].Moves[2].Locations, []int{18, 11})
+
+	_, err = ParsePDN(strings.NewReader("1. 9-40 *"))
+	assert.True(t, err != nil)
+
+}
@@ -0,0 +1,120 @@
+package checkersbot
+
+import (
+	"bytes"
+	"fmt"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+// Thinkers that also implement Ponderer get called while the other team
+// is to move, so they can use the opponent's moveInterval to prepare
+// answers to the replies they expect.  Ponder should return as soon as
+// possible once stop is closed, which happens when it becomes our turn.
+type Ponderer interface {
+	Ponder(gameState GameState, stop <-chan bool) []PonderedMove
+}
+
+// A speculative answer: if the opponent's move leaves the board looking
+// like ExpectedBoard, play Reply.
+type PonderedMove struct {
+	ExpectedBoard core.Board
+	Reply         core.Move
+}
+
+// Kick off the ponderer for the opponent's turn, unless the thinker isn't
+// a Ponderer, the game is over, the thinker is still busy or we are
+// already pondering this turn.  Pondered boards are core.Boards, so only
+// AMERICAN_CHECKERS games are pondered.
+func (game *Game) startPondering(gameState GameState) {
+
+	ponderer, ok := game.thinker.(Ponderer)
+	if !ok || gameState.WinningTeam != -1 || gameState.Variant != AMERICAN_CHECKERS {
+		return
+	}
+
+	game.isThinkingMutex.Lock()
+	defer game.isThinkingMutex.Unlock()
+
+	// the thinker may still be on our last turn, eg, after it timed out
+	if game.isPondering || game.isThinking || game.ponderedTurn == ponderTurnKey(gameState) {
+		return
+	}
+
+	game.debug("Calling ponderer", "ponderTurn", gameState.Turn)
+	game.isPondering = true
+	game.ponderedTurn = ponderTurnKey(gameState)
+	game.ponderCache = map[string]core.Move{}
+	stop := make(chan bool)
+	game.ponderStop = stop
+
+	go func() {
+		ponderedMoves := ponderer.Ponder(gameState, stop)
+		game.debug("Ponderer prepared replies", "replies", len(ponderedMoves))
+		game.isThinkingMutex.Lock()
+		for _, ponderedMove := range ponderedMoves {
+			game.ponderCache[boardKey(ponderedMove.ExpectedBoard)] = ponderedMove.Reply
+		}
+		game.isPondering = false
+		game.isThinkingCond.Broadcast()
+		game.isThinkingMutex.Unlock()
+	}()
+
+}
+
+// Tell a running ponderer to wrap up and wait until it has, so its replies
+// are in the cache before ponderedMove looks and the thinker isn't asked
+// to Think while it's still pondering.  The cache stays valid for the
+// turn it was pondering.
+func (game *Game) stopPondering() {
+	game.isThinkingMutex.Lock()
+	defer game.isThinkingMutex.Unlock()
+	if game.ponderStop != nil {
+		close(game.ponderStop)
+		game.ponderStop = nil
+	}
+	for game.isPondering {
+		game.isThinkingCond.Wait()
+	}
+}
+
+// If the opponent played one of the replies the ponderer expected, return
+// the move it prepared, looked up among our current valid moves.
+func (game *Game) ponderedMove(gameState GameState) (validMove ValidMove, ok bool) {
+
+	if gameState.Variant != AMERICAN_CHECKERS {
+		return
+	}
+	game.isThinkingMutex.Lock()
+	defer game.isThinkingMutex.Unlock()
+
+	key := boardKey(gameState.Export())
+	move, hit := game.ponderCache[key]
+	if !hit {
+		return
+	}
+	delete(game.ponderCache, key)
+
+	allValidMoves := gameState.Teams[game.ourTeamId].AllValidMoves()
+	index, err := FindValidMove(move, allValidMoves)
+	if err != nil {
+		// an ambiguous reply could be the wrong route, leave it to Think
+		game.warn("Pondered move can't be used, ignoring it", "err", err)
+		return
+	}
+	return allValidMoves[index], true
+
+}
+
+func ponderTurnKey(gameState GameState) string {
+	return fmt.Sprintf("%v/%v", gameState.Number, gameState.Turn)
+}
+
+// A string that uniquely identifies the pieces on a board
+func boardKey(board core.Board) string {
+	buf := bytes.Buffer{}
+	for location := 1; location <= 32; location++ {
+		fmt.Fprintf(&buf, "%d", board.PieceAt(GetCoreLocation(location)))
+	}
+	return buf.String()
+}
@@ -0,0 +1,125 @@
+package checkersbot
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+// Expects blue to play 22 -> 18 and prepares 14x23 as the answer
+type predictingThinker struct {
+	ponderCount int
+}
+
+func (p *predictingThinker) Think(gameState GameState) (ValidMove, bool) {
+	return ValidMove{}, false
+}
+
+func (p *predictingThinker) Ponder(gameState GameState, stop <-chan bool) []PonderedMove {
+	p.ponderCount++
+	board := gameState.Export()
+	from := GetCoreLocation(22)
+	to := GetCoreLocation(18)
+	board[to.Row()][to.Col()] = board[from.Row()][from.Col()]
+	board[from.Row()][from.Col()] = core.EMPTY
+	reply := core.NewMoveFromTo(GetCoreLocation(14), GetCoreLocation(23))
+	return []PonderedMove{{ExpectedBoard: board, Reply: reply}}
+}
+
+func ponderTestGameState(blueLocation int, activeTeam TeamType, turn int) GameState {
+	return GameState{
+		ActiveTeam:  activeTeam,
+		WinningTeam: -1,
+		Turn:        turn,
+		Teams: []Team{
+			{Pieces: []Piece{
+				{Location: 14, ValidMoves: []ValidMove{{Locations: []int{23}}}},
+			}},
+			{Pieces: []Piece{
+				{Location: blueLocation},
+			}},
+		},
+	}
+}
+
+func TestPonderedMove(t *testing.T) {
+
+	thinker := &predictingThinker{}
+	game := NewGame(RED_TEAM, thinker)
+
+	game.startPondering(ponderTestGameState(22, BLUE_TEAM, 2))
+	game.startPondering(ponderTestGameState(22, BLUE_TEAM, 2))
+	game.waitForThinkerToFinish()
+	assert.Equals(t, thinker.ponderCount, 1)
+
+	// opponent played something else
+	_, ok := game.ponderedMove(ponderTestGameState(17, RED_TEAM, 3))
+	assert.False(t, ok)
+
+	// opponent played the predicted reply
+	validMove, ok := game.ponderedMove(ponderTestGameState(18, RED_TEAM, 3))
+	assert.True(t, ok)
+	assert.Equals(t, validMove.StartLocation, 14)
+	assert.Equals(t, validMove.EndLocation(), 23)
+
+	// each pondered move is only used once
+	_, ok = game.ponderedMove(ponderTestGameState(18, RED_TEAM, 3))
+	assert.False(t, ok)
+
+}
+
+// Ponders until told to stop, then hands back what predictingThinker would
+type stoppablePonderer struct {
+	predictingThinker
+	started chan bool
+}
+
+func (p *stoppablePonderer) Ponder(gameState GameState, stop <-chan bool) []PonderedMove {
+	close(p.started)
+	<-stop
+	return p.predictingThinker.Ponder(gameState, stop)
+}
+
+func TestStopPonderingWaitsForReplies(t *testing.T) {
+
+	thinker := &stoppablePonderer{started: make(chan bool)}
+	game := NewGame(RED_TEAM, thinker)
+
+	game.startPondering(ponderTestGameState(22, BLUE_TEAM, 2))
+	<-thinker.started
+	game.stopPondering()
+
+	validMove, ok := game.ponderedMove(ponderTestGameState(18, RED_TEAM, 3))
+	assert.True(t, ok)
+	assert.Equals(t, validMove.EndLocation(), 23)
+
+}
+
+func TestPonderedMoveAmbiguous(t *testing.T) {
+
+	// 2 to 18 could go through 9 or 11, so the reply isn't used
+	gameState := ambiguousJumpGameState()
+	game := NewGame(RED_TEAM, &predictingThinker{})
+	game.ponderCache = map[string]core.Move{
+		boardKey(gameState.Export()): core.NewMoveFromTo(GetCoreLocation(2), GetCoreLocation(18)),
+	}
+	_, ok := game.ponderedMove(gameState)
+	assert.False(t, ok)
+
+}
+
+func TestNoPonderingWhileThinking(t *testing.T) {
+
+	thinker := &predictingThinker{}
+	game := NewGame(RED_TEAM, thinker)
+	game.isThinking = true
+	game.startPondering(ponderTestGameState(22, BLUE_TEAM, 2))
+	assert.False(t, game.isPondering)
+
+	game.isThinking = false
+	game.startPondering(ponderTestGameState(22, BLUE_TEAM, 2))
+	game.waitForThinkerToFinish()
+	assert.Equals(t, thinker.ponderCount, 1)
+
+}
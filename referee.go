@@ -0,0 +1,165 @@
+package checkersbot
+
+import (
+	"fmt"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+// Plays the part of the server for games played locally: sets up the
+// board, checks and applies moves and decides when a game is won, by the
+// rules of its variant.
+type Referee struct {
+	Variant   Variant
+	Objective Objective
+}
+
+func NewReferee(variant Variant) Referee {
+	return Referee{Variant: variant}
+}
+
+// A referee for giveaway games, where running out of pieces or moves wins
+func NewGiveawayReferee(variant Variant) Referee {
+	return Referee{Variant: variant, Objective: GIVEAWAY_OBJECTIVE}
+}
+
+// The position at the start of a game, with the first team's valid moves
+func (r Referee) InitialGameState() GameState {
+	gameState, err := NewVariantGameStateFromFEN(r.Variant, r.Variant.InitialFEN())
+	if err != nil {
+		panic(err)
+	}
+	gameState.Turn = 1
+	gameState.Objective = r.Objective
+	return PopulateValidMoves(gameState)
+}
+
+// Replay a game from the initial position.  See NewGameStateFromHistory.
+func (r Referee) Replay(moves []MoveHistory) (gameState GameState, err error) {
+	gameStates, err := r.GameStates(moves)
+	return gameStates[len(gameStates)-1], err
+}
+
+// The GameState at the start of a game and after each of the moves.  If a
+// move is illegal, the states up to it are returned with its
+// *IllegalMoveError.
+func (r Referee) GameStates(moves []MoveHistory) (gameStates []GameState, err error) {
+	gameState := r.InitialGameState()
+	gameStates = append(gameStates, gameState)
+	for i, move := range moves {
+		next, err := r.ApplyMove(gameState, move)
+		if err != nil {
+			if illegalMoveError, ok := err.(*IllegalMoveError); ok {
+				illegalMoveError.Index = i
+			}
+			return gameStates, err
+		}
+		gameStates = append(gameStates, next)
+		gameState = next
+	}
+	return gameStates, nil
+}
+
+// Play a move for the active team and return the resulting GameState,
+// with the next team's valid moves and the winner if the game is over.
+// The given GameState is left alone.  Returns an *IllegalMoveError if the
+// move isn't legal, with the index it would have had in Moves.
+func (r Referee) ApplyMove(gameState GameState, move MoveHistory) (GameState, error) {
+
+	illegal := func(format string, args ...interface{}) (GameState, error) {
+		return gameState, &IllegalMoveError{Index: len(gameState.Moves), Move: move, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	gameState.Variant = r.Variant
+	gameState.Objective = r.Objective
+	if gameState.WinningTeam != -1 {
+		return illegal("the game is over, %v won", gameState.WinningTeam)
+	}
+	if move.Team != gameState.ActiveTeam {
+		return illegal("it is %v's turn", gameState.ActiveTeam)
+	}
+	if len(move.Locations) < 2 {
+		return illegal("no destination")
+	}
+
+	var path *MovePath
+	for _, legal := range gameState.LegalMovePaths() {
+		if intsEqual(legal.Locations, move.Locations) {
+			legal := legal
+			path = &legal
+			break
+		}
+	}
+	if path == nil {
+		return illegal("not a legal move in %v", gameState.FEN())
+	}
+
+	if r.Variant == AMERICAN_CHECKERS {
+		board := gameState.Export()
+		coreMove := core.NewMoveFromTo(GetCoreLocation(path.Locations[0]), GetCoreLocation(path.Locations[len(path.Locations)-1]))
+		coreAgrees := false
+		for _, legal := range board.LegalMoves(GetCorePlayer(move.Team)) {
+			if legal.From().Equals(coreMove.From()) && legal.To().Equals(coreMove.To()) {
+				coreAgrees = true
+				break
+			}
+		}
+		if !coreAgrees {
+			return illegal("checkers-core does not allow it in %v", gameState.FEN())
+		}
+	}
+
+	pieceIndex := -1
+	for index, piece := range gameState.Teams[move.Team].Pieces {
+		if !piece.Captured && piece.Location == path.Locations[0] {
+			pieceIndex = index
+			break
+		}
+	}
+	if move.Piece >= 0 && move.Piece != pieceIndex {
+		return illegal("piece %v is on %v, not piece %v", pieceIndex, path.Locations[0], move.Piece)
+	}
+
+	next := gameState
+	next.Teams = make([]Team, len(gameState.Teams))
+	for teamIndex, team := range gameState.Teams {
+		next.Teams[teamIndex] = team
+		next.Teams[teamIndex].Pieces = append([]Piece{}, team.Pieces...)
+	}
+
+	team := &next.Teams[move.Team]
+	team.Pieces[pieceIndex].Location = path.Locations[len(path.Locations)-1]
+	if path.Crowned {
+		team.Pieces[pieceIndex].King = true
+	}
+	opponent := &next.Teams[move.Team.Opponent()]
+	for _, capturedLocation := range path.Captured {
+		for index, piece := range opponent.Pieces {
+			if !piece.Captured && piece.Location == capturedLocation {
+				opponent.Pieces[index].Captured = true
+			}
+		}
+	}
+
+	played := move
+	played.Piece = pieceIndex
+	played.Turn = gameState.Turn
+	next.Moves = append(append([]MoveHistory{}, gameState.Moves...), played)
+	next.Turn++
+	next.ActiveTeam = move.Team.Opponent()
+
+	next = PopulateValidMoves(next)
+	next.WinningTeam = r.Winner(next)
+	return next, nil
+
+}
+
+// The team that has won, or -1 while the game goes on.  The game is over
+// when the team to move has no legal move, which includes having no
+// pieces left, and that team loses, or in giveaway wins.
+func (r Referee) Winner(gameState GameState) TeamType {
+	if len(gameState.LegalMovePaths()) == 0 {
+		return r.Objective.WinnerWhenStuck(gameState.ActiveTeam)
+	}
+	return -1
+}
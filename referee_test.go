@@ -0,0 +1,76 @@
+package checkersbot
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestRefereeInternational(t *testing.T) {
+
+	referee := NewReferee(INTERNATIONAL_DRAUGHTS)
+	gameState, err := referee.Replay([]MoveHistory{
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{32, 28}},
+		{Piece: -1, Team: RED_TEAM, Locations: []int{19, 23}},
+	})
+	assert.True(t, err == nil)
+	assert.Equals(t, gameState.Variant, INTERNATIONAL_DRAUGHTS)
+	assert.Equals(t, gameState.ActiveTeam, BLUE_TEAM)
+	assert.Equals(t, gameState.Turn, 3)
+	assert.DeepEquals(t, validMoveKeys(gameState.Teams[BLUE_TEAM].AllValidMoves()), []string{"28:[19]"})
+
+	// the jump is compulsory
+	_, err = referee.ApplyMove(gameState, MoveHistory{Piece: -1, Team: BLUE_TEAM, Locations: []int{31, 27}})
+	illegalMoveError, ok := err.(*IllegalMoveError)
+	assert.True(t, ok)
+	assert.Equals(t, illegalMoveError.Index, 2)
+
+	next, err := referee.ApplyMove(gameState, MoveHistory{Piece: -1, Team: BLUE_TEAM, Locations: []int{28, 19}})
+	assert.True(t, err == nil)
+	assert.Equals(t, next.PieceCount(), 39)
+	assert.Equals(t, gameState.PieceCount(), 40)
+
+}
+
+func TestRefereeWinner(t *testing.T) {
+
+	referee := NewReferee(AMERICAN_CHECKERS)
+	gameState := PopulateValidMoves(MustGameStateFromFEN("W:W18:B14"))
+	next, err := referee.ApplyMove(gameState, MoveHistory{Piece: -1, Team: BLUE_TEAM, Locations: []int{18, 9}})
+	assert.True(t, err == nil)
+	assert.Equals(t, next.WinningTeam, BLUE_TEAM)
+
+	_, err = referee.ApplyMove(next, MoveHistory{Piece: -1, Team: RED_TEAM, Locations: []int{1, 5}})
+	assert.True(t, err != nil)
+
+}
+
+func TestRefereeGameStates(t *testing.T) {
+
+	moves := []MoveHistory{
+		{Piece: -1, Team: RED_TEAM, Locations: []int{10, 14}},
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{23, 19}},
+		{Piece: -1, Team: RED_TEAM, Locations: []int{14, 18}},
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{22, 15}},
+	}
+	referee := NewReferee(AMERICAN_CHECKERS)
+	gameStates, err := referee.GameStates(moves)
+	assert.True(t, err == nil)
+	assert.Equals(t, len(gameStates), 5)
+	assert.Equals(t, gameStates[0].FEN(), INITIAL_FEN)
+
+	// 22x15 jumped the red man on 18
+	assert.Equals(t, gameStates[4].FEN(), "B:W15,19,21,24,25,26,27,28,29,30,31,32:B1,2,3,4,5,6,7,8,9,11,12")
+
+	// the states up to an illegal move come back with the error
+	gameStates, err = referee.GameStates(append(moves[:2:2], MoveHistory{Piece: -1, Team: RED_TEAM, Locations: []int{14, 23}}))
+	_, ok := err.(*IllegalMoveError)
+	assert.True(t, ok)
+	assert.Equals(t, len(gameStates), 3)
+
+	// crowning
+	gameState, err := referee.ApplyMove(MustGameStateFromFEN("B:W32:B27"), MoveHistory{Piece: -1, Team: RED_TEAM, Locations: []int{27, 31}})
+	assert.True(t, err == nil)
+	assert.True(t, gameState.Teams[RED_TEAM].Pieces[0].King)
+
+}
@@ -0,0 +1,160 @@
+package checkersbot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+type RenderStyle int
+
+const (
+	RENDER_ASCII = RenderStyle(iota)
+	RENDER_UNICODE
+)
+
+type RenderOptions struct {
+	Style RenderStyle
+
+	// Show the 1-32 location numbers on the dark squares
+	SquareNumbers bool
+
+	// The team whose side of the board is drawn at the bottom
+	Perspective TeamType
+
+	// Mark the squares the last move in Moves passed through with a *
+	HighlightLastMove bool
+
+	// Mark pieces that have ValidMoves with a +
+	MarkMovablePieces bool
+}
+
+// What the logs use: ASCII from BLUE's side with everything switched on
+var DefaultRenderOptions = RenderOptions{
+	Style:             RENDER_ASCII,
+	SquareNumbers:     true,
+	Perspective:       BLUE_TEAM,
+	HighlightLastMove: true,
+	MarkMovablePieces: true,
+}
+
+type renderCharset struct {
+	redMan, redKing, blueMan, blueKing, empty string
+	top, middle, bottom                       [3]string // left, junction, right
+	horizontal, vertical                      string
+}
+
+var asciiCharset = renderCharset{
+	redMan: "r", redKing: "R", blueMan: "b", blueKing: "B", empty: ".",
+	top:        [3]string{"+", "+", "+"},
+	middle:     [3]string{"+", "+", "+"},
+	bottom:     [3]string{"+", "+", "+"},
+	horizontal: "-",
+	vertical:   "|",
+}
+
+// RED plays the black pieces and BLUE the white ones, as in PDN
+var unicodeCharset = renderCharset{
+	redMan: "⛂", redKing: "⛃", blueMan: "⛀", blueKing: "⛁", empty: "·",
+	top:        [3]string{"┌", "┬", "┐"},
+	middle:     [3]string{"├", "┼", "┤"},
+	bottom:     [3]string{"└", "┴", "┘"},
+	horizontal: "─",
+	vertical:   "│",
+}
+
+// Draw the board, at the size of the game's variant.  Each dark square
+// shows a marker, the piece and the location number, eg, "+r 9" is a RED
+// man on 9 that can move.
+func (gamestate GameState) Render(options RenderOptions) string {
+
+	charset := asciiCharset
+	if options.Style == RENDER_UNICODE {
+		charset = unicodeCharset
+	}
+
+	board := gamestate.ExportVariantBoard()
+	geometry := gamestate.Variant.Geometry()
+	size := geometry.Size
+	lastMoveSquares := map[int]bool{}
+	if options.HighlightLastMove && len(gamestate.Moves) > 0 {
+		for _, location := range gamestate.Moves[len(gamestate.Moves)-1].Locations {
+			lastMoveSquares[location] = true
+		}
+	}
+	movableSquares := map[int]bool{}
+	if options.MarkMovablePieces {
+		for _, team := range gamestate.Teams {
+			for _, piece := range team.Pieces {
+				if !piece.Captured && len(piece.ValidMoves) > 0 {
+					movableSquares[piece.Location] = true
+				}
+			}
+		}
+	}
+
+	border := func(corners [3]string) string {
+		cells := make([]string, size)
+		for i := range cells {
+			cells[i] = strings.Repeat(charset.horizontal, 4)
+		}
+		return corners[0] + strings.Join(cells, corners[1]) + corners[2] + "\n"
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteString(border(charset.top))
+	for displayRow := 0; displayRow < size; displayRow++ {
+		buf.WriteString(charset.vertical)
+		for displayCol := 0; displayCol < size; displayCol++ {
+			row, col := displayRow, displayCol
+			if options.Perspective == RED_TEAM {
+				row, col = size-1-displayRow, size-1-displayCol
+			}
+			location := geometry.Location(core.NewLocation(row, col))
+			if location == -1 {
+				buf.WriteString("    " + charset.vertical)
+				continue
+			}
+
+			marker := " "
+			switch {
+			case lastMoveSquares[location]:
+				marker = "*"
+			case movableSquares[location]:
+				marker = "+"
+			}
+
+			symbol := charset.empty
+			switch board.PieceAt(location) {
+			case core.BLACK:
+				symbol = charset.redMan
+			case core.BLACK_KING:
+				symbol = charset.redKing
+			case core.RED:
+				symbol = charset.blueMan
+			case core.RED_KING:
+				symbol = charset.blueKing
+			}
+
+			number := "  "
+			if options.SquareNumbers {
+				number = fmt.Sprintf("%2d", location)
+			}
+			buf.WriteString(marker + symbol + number + charset.vertical)
+		}
+		buf.WriteString("\n")
+		if displayRow < size-1 {
+			buf.WriteString(border(charset.middle))
+		}
+	}
+	buf.WriteString(border(charset.bottom))
+	return buf.String()
+
+}
+
+// Render with the DefaultRenderOptions
+func (gamestate GameState) RenderString() string {
+	return gamestate.Render(DefaultRenderOptions)
+}
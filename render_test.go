@@ -0,0 +1,62 @@
+package checkersbot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+func TestRenderASCII(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("W:W18,K30:B14")
+	gameState.Teams[BLUE_TEAM].Pieces[0].ValidMoves = []ValidMove{{Locations: []int{9}}}
+	gameState.Moves = []MoveHistory{{Team: RED_TEAM, Locations: []int{10, 14}}}
+
+	rendered := gameState.Render(DefaultRenderOptions)
+	lines := strings.Split(strings.TrimSuffix(rendered, "\n"), "\n")
+	assert.Equals(t, len(lines), 17)
+	assert.Equals(t, lines[0], "+----+----+----+----+----+----+----+----+")
+	assert.Equals(t, lines[1], "|    | . 1|    | . 2|    | . 3|    | . 4|")
+
+	// last move 10->14 highlighted, movable piece on 18 marked
+	assert.Equals(t, lines[5], "|    | . 9|    |*.10|    | .11|    | .12|")
+	assert.Equals(t, lines[7], "| .13|    |*r14|    | .15|    | .16|    |")
+	assert.Equals(t, lines[9], "|    | .17|    |+b18|    | .19|    | .20|")
+	assert.Equals(t, lines[15], "| .29|    | B30|    | .31|    | .32|    |")
+
+	// from RED's side square 32 is in the top left corner
+	options := DefaultRenderOptions
+	options.Perspective = RED_TEAM
+	options.SquareNumbers = false
+	lines = strings.Split(gameState.Render(options), "\n")
+	assert.Equals(t, lines[1], "|    | .  |    | .  |    | B  |    | .  |")
+
+}
+
+func TestRenderUnicode(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("B:WK1:B32")
+	options := DefaultRenderOptions
+	options.Style = RENDER_UNICODE
+	rendered := gameState.Render(options)
+	assert.True(t, strings.HasPrefix(rendered, "┌────┬"))
+	assert.True(t, strings.Contains(rendered, "│ ⛁ 1│"))
+	assert.True(t, strings.Contains(rendered, "│ ⛂32│"))
+
+}
+
+func TestRenderInternational(t *testing.T) {
+
+	gameState, err := NewVariantGameStateFromFEN(INTERNATIONAL_DRAUGHTS, "W:W46,K50:B1,5")
+	assert.True(t, err == nil)
+	lines := strings.Split(strings.TrimSuffix(gameState.RenderString(), "\n"), "\n")
+	assert.Equals(t, len(lines), 21)
+	assert.Equals(t, lines[1], "|    | r 1|    | . 2|    | . 3|    | . 4|    | r 5|")
+	assert.Equals(t, lines[19], "| b46|    | .47|    | .48|    | .49|    | B50|    |")
+
+	// Export can't fit it on a core.Board
+	assert.Equals(t, gameState.Export(), core.NewEmptyBoard())
+
+}
@@ -0,0 +1,323 @@
+package checkersbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long a replay waits for the move once the thinker has finished.
+// Pondered moves and the thinker's own move reach the game loop from a
+// goroutine, so they can show up just after it's done.
+const REPLAY_MOVE_GRACE = time.Second
+
+// A turn where the thinker's vote isn't the move that was played
+type ReplayDisagreement struct {
+	Turn int
+
+	// The position the thinker was asked about
+	FEN string
+
+	Played MoveHistory
+
+	// The locations the thinker voted for, nil if it didn't vote
+	Voted []int
+}
+
+func (d ReplayDisagreement) String() string {
+	voted := "nothing"
+	if len(d.Voted) > 0 {
+		voted = PDNMoveString(MoveHistory{Locations: d.Voted})
+	}
+	return fmt.Sprintf("turn %v %v: played %v, voted %v", d.Turn, d.FEN, PDNMoveString(d.Played), voted)
+}
+
+// How the thinker did against a game that was already played.  Only our
+// turns with a move played after them count.
+type ReplayReport struct {
+	GameNumber    int
+	Team          TeamType
+	Turns         int
+	Agreed        int
+	Votes         []OutgoingVotes
+	Disagreements []ReplayDisagreement
+}
+
+// The fraction of turns where the thinker voted for the move that was
+// played, 0 if there were none
+func (report ReplayReport) AgreementRate() float64 {
+	if report.Turns == 0 {
+		return 0
+	}
+	return float64(report.Agreed) / float64(report.Turns)
+}
+
+func (report ReplayReport) String() string {
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "Game #: %v team %v agreed on %v of %v turns (%.0f%%)\n", report.GameNumber, report.Team, report.Agreed, report.Turns, 100*report.AgreementRate())
+	for _, disagreement := range report.Disagreements {
+		fmt.Fprintf(&buf, "  %v\n", disagreement)
+	}
+	return buf.String()
+}
+
+// Play the thinker as team through revisions of a game doc, in order.
+// Each revision goes through handleChanges like a change from the feed
+// would, and on our turns the vote is posted to a stand in for the
+// server and compared with the move the later revisions show was played.
+// Revisions with the same game number, turn and winner as the one before
+// are skipped, so each turn is thought about once.
+func ReplayGameStates(team TeamType, thinker Thinker, gameStates []GameState) (ReplayReport, error) {
+
+	if len(gameStates) == 0 {
+		return ReplayReport{}, fmt.Errorf("no game states to replay")
+	}
+
+	server := newReplayServer()
+	game := NewGame(team, thinker)
+	game.server = server
+	game.user = User{Id: "user:replay", TeamId: team}
+	_, userRev, err := server.Insert(game.user)
+	if err != nil {
+		return ReplayReport{}, err
+	}
+	game.user.Rev = userRev
+
+	report := ReplayReport{GameNumber: gameStates[0].Number, Team: team}
+	movesChan := make(chan ValidMove, 1)
+	var previous *GameState
+
+	for i, gameState := range gameStates {
+
+		if previous != nil && previous.Number == gameState.Number && previous.Turn == gameState.Turn && previous.WinningTeam == gameState.WinningTeam {
+			continue
+		}
+		previous = &gameStates[i]
+
+		server.setGameState(gameState)
+		if game.handleChanges(replayChanges(i, gameState.Rev), movesChan) {
+			break
+		}
+		if game.gameState.ActiveTeam != team || game.gameState.WinningTeam != -1 {
+			continue
+		}
+
+		votesBefore := server.voteCount()
+		if move, ok := game.awaitReplayMove(movesChan); ok {
+			game.PostChosenMove(game.OutgoingVoteFromMove(move))
+		}
+		var voted []int
+		if votes := server.votesSince(votesBefore); len(votes) > 0 {
+			voted = votes[len(votes)-1].Locations
+		}
+
+		played, ok := playedMove(gameStates[i+1:], gameState.Number, team, gameState.Turn)
+		if !ok {
+			continue
+		}
+		report.Turns++
+		if voted != nil && intsEqual(voted, played.Locations) {
+			report.Agreed++
+			continue
+		}
+		report.Disagreements = append(report.Disagreements, ReplayDisagreement{
+			Turn:   gameState.Turn,
+			FEN:    gameState.FEN(),
+			Played: played,
+			Voted:  voted,
+		})
+
+	}
+
+	game.stopPondering()
+	game.waitForThinkerToFinish()
+	report.Votes = server.votesSince(0)
+	return report, nil
+
+}
+
+// Replay an archived game, ending on its result if that was recorded
+func ReplayArchivedGame(team TeamType, thinker Thinker, archivedGame ArchivedGame) (ReplayReport, error) {
+	gameStates := archivedGame.GameStates()
+	if result, ok := archivedGame.Result(); ok {
+		if len(gameStates) == 0 || gameStates[len(gameStates)-1].Rev != result.Rev {
+			gameStates = append(gameStates, result)
+		}
+	}
+	return ReplayGameStates(team, thinker, gameStates)
+}
+
+func ReplayPDNGame(team TeamType, thinker Thinker, pdnGame PDNGame) (ReplayReport, error) {
+	gameStates, err := PDNGameStates(pdnGame)
+	if err != nil {
+		return ReplayReport{}, err
+	}
+	return ReplayGameStates(team, thinker, gameStates)
+}
+
+// The game doc as it would have been after each move of a PDN game,
+// starting from the initial position.  A result in the PDN that the
+// moves don't account for, eg, a resignation, goes on the last one.
+func PDNGameStates(pdnGame PDNGame) (gameStates []GameState, err error) {
+
+	referee := NewReferee(AMERICAN_CHECKERS)
+	gameState := referee.InitialGameState()
+	gameState.Number = pdnGame.GameNumber()
+	gameState.StartTime = pdnGame.StartTime()
+	gameState.Rev = "1-pdn"
+	gameStates = append(gameStates, gameState)
+
+	for i, move := range pdnGame.Moves {
+		next, err := referee.ApplyMove(gameState, move)
+		if err != nil {
+			if illegalMoveError, ok := err.(*IllegalMoveError); ok {
+				illegalMoveError.Index = i
+			}
+			return gameStates, err
+		}
+		next.Rev = fmt.Sprintf("%v-pdn", i+2)
+		gameStates = append(gameStates, next)
+		gameState = next
+	}
+
+	last := &gameStates[len(gameStates)-1]
+	if last.WinningTeam == -1 {
+		last.WinningTeam = pdnGame.WinningTeam()
+	}
+	return gameStates, nil
+
+}
+
+// Wait for the move the game loop would get on movesChan
+func (game *Game) awaitReplayMove(movesChan chan ValidMove) (ValidMove, bool) {
+	game.waitForThinkerToFinish()
+	select {
+	case move := <-movesChan:
+		return move, true
+	case <-time.After(REPLAY_MOVE_GRACE):
+		return ValidMove{}, false
+	}
+}
+
+// The move team played on turn, according to the first later revision
+// of the same game that has it
+func playedMove(later []GameState, gameNumber int, team TeamType, turn int) (MoveHistory, bool) {
+	for _, gameState := range later {
+		if gameState.Number != gameNumber {
+			break
+		}
+		for _, move := range gameState.Moves {
+			if move.Turn == turn && move.Team == team {
+				return move, true
+			}
+		}
+	}
+	return MoveHistory{}, false
+}
+
+// A changes feed result saying the game doc changed
+func replayChanges(seq int, rev string) Changes {
+	return Changes{
+		"results": []interface{}{
+			map[string]interface{}{
+				"seq":     seq,
+				"id":      GAME_DOC_ID,
+				"changes": []interface{}{map[string]interface{}{"rev": rev}},
+			},
+		},
+		"last_seq": seq,
+	}
+}
+
+// Stands in for the server during a replay.  It serves the current
+// revision of the game doc, and keeps the other docs the game writes,
+// noting each vote.
+type replayServer struct {
+	mutex     sync.Mutex
+	gameState []byte
+	docs      map[string][]byte
+	revs      map[string]int
+	votes     []OutgoingVotes
+}
+
+func newReplayServer() *replayServer {
+	return &replayServer{docs: map[string][]byte{}, revs: map[string]int{}}
+}
+
+func (s *replayServer) setGameState(gameState GameState) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.gameState, _ = json.Marshal(gameState)
+}
+
+func (s *replayServer) Retrieve(id string, doc interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	body := s.docs[id]
+	if id == GAME_DOC_ID {
+		body = s.gameState
+	}
+	if body == nil {
+		return fmt.Errorf("404 not found: %v", id)
+	}
+	return json.Unmarshal(body, doc)
+}
+
+func (s *replayServer) Insert(doc interface{}) (id string, rev string, err error) {
+	return s.put(doc)
+}
+
+func (s *replayServer) Edit(doc interface{}) (rev string, err error) {
+	_, rev, err = s.put(doc)
+	return
+}
+
+func (s *replayServer) put(doc interface{}) (id string, rev string, err error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return "", "", err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", "", err
+	}
+	id, _ = fields["_id"].(string)
+	if id == "" || id == GAME_DOC_ID {
+		return "", "", fmt.Errorf("replay can't store doc %q", id)
+	}
+
+	s.revs[id]++
+	rev = fmt.Sprintf("%v-replay", s.revs[id])
+	fields["_rev"] = rev
+	if s.docs[id], err = json.Marshal(fields); err != nil {
+		return "", "", err
+	}
+
+	if strings.HasPrefix(id, "vote:") {
+		votes := OutgoingVotes{}
+		json.Unmarshal(body, &votes)
+		votes.Rev = rev
+		s.votes = append(s.votes, votes)
+	}
+	return id, rev, nil
+
+}
+
+func (s *replayServer) voteCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.votes)
+}
+
+func (s *replayServer) votesSince(count int) []OutgoingVotes {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]OutgoingVotes{}, s.votes[count:]...)
+}
@@ -0,0 +1,117 @@
+package checkersbot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+// Plays the given path on each turn
+type scriptedThinker struct {
+	paths map[int][]int
+}
+
+func (s scriptedThinker) Think(gameState GameState) (ValidMove, bool) {
+	for _, validMove := range gameState.Teams[gameState.ActiveTeam].AllValidMoves() {
+		if intsEqual(validMove.Path(), s.paths[gameState.Turn]) {
+			return validMove, true
+		}
+	}
+	return ValidMove{}, false
+}
+
+const replayTestPDN = `[GameNumber "42"]
+[Result "1-0"]
+
+1. 11-15 22-18 2. 15x22 25x18 3. 8-11 1-0
+`
+
+func TestPDNGameStates(t *testing.T) {
+
+	pdnGame, err := ParsePDNGame(strings.NewReader(replayTestPDN))
+	assert.True(t, err == nil)
+	gameStates, err := PDNGameStates(pdnGame)
+	assert.True(t, err == nil)
+	assert.Equals(t, len(gameStates), 6)
+	assert.Equals(t, gameStates[0].Number, 42)
+	assert.Equals(t, gameStates[0].Turn, 1)
+	assert.Equals(t, gameStates[0].FEN(), INITIAL_FEN)
+	assert.Equals(t, gameStates[5].Rev, "6-pdn")
+	assert.Equals(t, len(gameStates[5].Moves), 5)
+	assert.Equals(t, gameStates[4].WinningTeam, TeamType(-1))
+	assert.Equals(t, gameStates[5].WinningTeam, BLUE_TEAM)
+
+	pdnGame.Moves[1].Locations = []int{22, 17, 13}
+	_, err = PDNGameStates(pdnGame)
+	illegalMoveError, ok := err.(*IllegalMoveError)
+	assert.True(t, ok)
+	assert.Equals(t, illegalMoveError.Index, 1)
+
+}
+
+func TestReplayPDNGame(t *testing.T) {
+
+	pdnGame, err := ParsePDNGame(strings.NewReader(replayTestPDN))
+	assert.True(t, err == nil)
+	thinker := scriptedThinker{paths: map[int][]int{
+		1: {11, 15},
+		3: {15, 22},
+		5: {9, 13},
+	}}
+
+	report, err := ReplayPDNGame(RED_TEAM, thinker, pdnGame)
+	assert.True(t, err == nil)
+	assert.Equals(t, report.GameNumber, 42)
+	assert.Equals(t, report.Team, RED_TEAM)
+	assert.Equals(t, report.Turns, 3)
+	assert.Equals(t, report.Agreed, 2)
+	assert.Equals(t, len(report.Votes), 3)
+	assert.DeepEquals(t, report.Votes[2].Locations, []int{9, 13})
+	assert.Equals(t, report.Votes[2].Turn, 5)
+
+	assert.Equals(t, len(report.Disagreements), 1)
+	disagreement := report.Disagreements[0]
+	assert.Equals(t, disagreement.Turn, 5)
+	assert.DeepEquals(t, disagreement.Played.Locations, []int{8, 11})
+	assert.DeepEquals(t, disagreement.Voted, []int{9, 13})
+	assert.True(t, strings.HasSuffix(disagreement.String(), "played 8-11, voted 9-13"))
+	assert.True(t, strings.HasPrefix(report.String(), "Game #: 42 team RED agreed on 2 of 3 turns (67%)"))
+
+}
+
+func TestReplayArchivedGame(t *testing.T) {
+
+	pdnGame, err := ParsePDNGame(strings.NewReader(replayTestPDN))
+	assert.True(t, err == nil)
+	gameStates, err := PDNGameStates(pdnGame)
+	assert.True(t, err == nil)
+
+	archive, err := NewGameArchive(t.TempDir())
+	assert.True(t, err == nil)
+	for _, gameState := range gameStates[:len(gameStates)-1] {
+		assert.True(t, archive.RecordGameState(RED_TEAM, gameState) == nil)
+		// a second revision of the same turn is thought about once
+		gameState.Rev += "-again"
+		assert.True(t, archive.RecordGameState(RED_TEAM, gameState) == nil)
+	}
+	assert.True(t, archive.RecordResult(RED_TEAM, gameStates[len(gameStates)-1]) == nil)
+	archivedGame, err := archive.ReadGame(42)
+	assert.True(t, err == nil)
+
+	thinker := scriptedThinker{paths: map[int][]int{
+		2: {22, 18},
+		4: {26, 17},
+	}}
+	report, err := ReplayArchivedGame(BLUE_TEAM, thinker, archivedGame)
+	assert.True(t, err == nil)
+	assert.Equals(t, report.Turns, 2)
+	assert.Equals(t, report.Agreed, 1)
+	assert.Equals(t, len(report.Votes), 2)
+	assert.Equals(t, len(report.Disagreements), 1)
+	assert.DeepEquals(t, report.Disagreements[0].Played.Locations, []int{25, 18})
+
+	_, err = ReplayGameStates(BLUE_TEAM, thinker, nil)
+	assert.True(t, err != nil)
+
+}
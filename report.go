@@ -0,0 +1,244 @@
+package checkersbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// How one team did over a game
+type TeamReport struct {
+	Team  TeamType `json:"team"`
+	Moves int      `json:"moves"`
+
+	// Opponent pieces this team took
+	Captures int `json:"captures"`
+
+	KingsMade int `json:"kingsMade"`
+
+	// The pieces left at the end
+	Men   int `json:"men"`
+	Kings int `json:"kings"`
+}
+
+// The statistics for a finished game, from our team's point of view.
+// Observers that implement GameReportObserver get one when the Game sees
+// the game has a winner.
+type GameReport struct {
+	GameNumber  int          `json:"gameNumber"`
+	Team        TeamType     `json:"team"`
+	WinningTeam TeamType     `json:"winningTeam"`
+	Objective   Objective    `json:"objective"`
+	MovesPlayed int          `json:"movesPlayed"`
+	Teams       []TeamReport `json:"teams"`
+
+	// How long our thinker took on the turns it was asked about
+	ThinkCount          int     `json:"thinkCount"`
+	AverageThinkSeconds float64 `json:"averageThinkSeconds"`
+	MaxThinkSeconds     float64 `json:"maxThinkSeconds"`
+
+	// Votes we posted, the turns we voted on that our team then moved on,
+	// and how many of those moves were the one we voted for, as in the
+	// game's InfluenceStats
+	VotesPosted   int     `json:"votesPosted"`
+	VotedTurns    int     `json:"votedTurns"`
+	VotesPlayed   int     `json:"votesPlayed"`
+	AgreementRate float64 `json:"agreementRate"`
+}
+
+// The report for a game as far as the GameState tells it, ie, without
+// think times and votes
+func NewGameReport(team TeamType, gameState GameState) GameReport {
+
+	report := GameReport{
+		GameNumber:  gameState.Number,
+		Team:        team,
+		WinningTeam: gameState.WinningTeam,
+		Objective:   gameState.Objective,
+		MovesPlayed: len(gameState.Moves),
+		Teams:       []TeamReport{{Team: RED_TEAM}, {Team: BLUE_TEAM}},
+	}
+
+	for _, move := range gameState.Moves {
+		if move.Team == RED_TEAM || move.Team == BLUE_TEAM {
+			report.Teams[move.Team].Moves++
+		}
+	}
+
+	for teamIndex, team := range gameState.Teams {
+		if teamIndex > int(BLUE_TEAM) {
+			break
+		}
+		teamReport := &report.Teams[teamIndex]
+		opponentReport := &report.Teams[TeamType(teamIndex).Opponent()]
+		for _, piece := range team.Pieces {
+			if piece.King {
+				teamReport.KingsMade++
+			}
+			switch {
+			case piece.Captured:
+				opponentReport.Captures++
+			case piece.King:
+				teamReport.Kings++
+			default:
+				teamReport.Men++
+			}
+		}
+	}
+
+	return report
+
+}
+
+func (report GameReport) String() string {
+
+	buf := bytes.Buffer{}
+	red, blue := report.Teams[RED_TEAM], report.Teams[BLUE_TEAM]
+	fmt.Fprintf(&buf, "Game #: %v won by %v, we were %v", report.GameNumber, report.WinningTeam, report.Team)
+	if report.Objective != STANDARD_OBJECTIVE {
+		fmt.Fprintf(&buf, " (%v)", report.Objective)
+	}
+	fmt.Fprintf(&buf, "\n")
+	fmt.Fprintf(&buf, "Moves: %v (RED %v, BLUE %v)\n", report.MovesPlayed, red.Moves, blue.Moves)
+	fmt.Fprintf(&buf, "Captures: RED %v, BLUE %v\n", red.Captures, blue.Captures)
+	fmt.Fprintf(&buf, "Kings made: RED %v, BLUE %v\n", red.KingsMade, blue.KingsMade)
+	fmt.Fprintf(&buf, "Final material: RED %v men %v kings, BLUE %v men %v kings\n", red.Men, red.Kings, blue.Men, blue.Kings)
+	fmt.Fprintf(&buf, "Think time: average %v, max %v over %v turns\n", secondsDuration(report.AverageThinkSeconds), secondsDuration(report.MaxThinkSeconds), report.ThinkCount)
+	fmt.Fprintf(&buf, "Votes: %v posted, played on %v of %v turns (%.0f%%)\n", report.VotesPosted, report.VotesPlayed, report.VotedTurns, 100*report.AgreementRate)
+	return buf.String()
+
+}
+
+// Write the report as indented JSON
+func (report GameReport) WriteJSON(w io.Writer) error {
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(encoded, '\n'))
+	return err
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
+
+// What the Game notes during a game for its GameReport and InfluenceStats
+type gameStats struct {
+	mutex      sync.Mutex
+	gameNumber int
+	thinkCount int
+	thinkTotal time.Duration
+	thinkMax   time.Duration
+	votes      int
+	votedPaths map[int][]int
+	reported   bool
+
+	// Votes matched up with the move played, see matchVotes.  The totals
+	// carry on from game to game.
+	matchedTurns map[int]bool
+	matchedCount int
+	agreedCount  int
+	totalMatched int
+	totalAgreed  int
+}
+
+// Start over when the game number changes.  Call with the mutex held.
+func (stats *gameStats) forGame(gameNumber int) {
+	if stats.votedPaths == nil || stats.gameNumber != gameNumber {
+		stats.gameNumber = gameNumber
+		stats.thinkCount = 0
+		stats.thinkTotal = 0
+		stats.thinkMax = 0
+		stats.votes = 0
+		stats.votedPaths = map[int][]int{}
+		stats.reported = false
+		stats.matchedTurns = map[int]bool{}
+		stats.matchedCount = 0
+		stats.agreedCount = 0
+	}
+}
+
+func (game *Game) recordThinkTime(gameNumber int, thinkTime time.Duration) {
+	stats := &game.stats
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	stats.forGame(gameNumber)
+	stats.thinkCount++
+	stats.thinkTotal += thinkTime
+	if thinkTime > stats.thinkMax {
+		stats.thinkMax = thinkTime
+	}
+}
+
+func (game *Game) recordStatsVote(votes OutgoingVotes) {
+	stats := &game.stats
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	stats.forGame(votes.GameId)
+	stats.votes++
+	stats.votedPaths[votes.Turn] = votes.Locations
+}
+
+// The report for a finished game, with what the Game noted during it
+func (game *Game) gameReport(gameState GameState) GameReport {
+
+	report := NewGameReport(game.ourTeamId, gameState)
+
+	stats := &game.stats
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+	stats.forGame(gameState.Number)
+
+	report.ThinkCount = stats.thinkCount
+	if stats.thinkCount > 0 {
+		report.AverageThinkSeconds = (stats.thinkTotal / time.Duration(stats.thinkCount)).Seconds()
+	}
+	report.MaxThinkSeconds = stats.thinkMax.Seconds()
+	report.VotesPosted = stats.votes
+	influence := stats.influence()
+	report.VotedTurns = influence.GameTurns
+	report.VotesPlayed = influence.GameAgreed
+	report.AgreementRate = influence.GameRate()
+	return report
+
+}
+
+// Hand the report for a finished game to the observers, once per game
+func (game *Game) reportGame(gameState GameState) {
+
+	game.stats.mutex.Lock()
+	game.stats.forGame(gameState.Number)
+	reported := game.stats.reported
+	game.stats.reported = true
+	game.stats.mutex.Unlock()
+	if reported {
+		return
+	}
+
+	// so the votes on the last moves are in the report
+	game.matchVotes(gameState)
+	report := game.gameReport(gameState)
+	game.info("Game report", "winningTeam", report.WinningTeam, "moves", report.MovesPlayed, "agreementRate", report.AgreementRate)
+	for _, observer := range game.optionalObservers() {
+		if observer, ok := observer.(GameReportObserver); ok {
+			observer.GameReported(report)
+		}
+	}
+
+}
+
+// The thinker and the added lifecycle observers, to check for the
+// optional observer interfaces
+func (game *Game) optionalObservers() []interface{} {
+	observers := []interface{}{game.thinker}
+	game.observersMutex.Lock()
+	defer game.observersMutex.Unlock()
+	for _, observer := range game.observers {
+		observers = append(observers, observer)
+	}
+	return observers
+}
@@ -0,0 +1,90 @@
+package checkersbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+type reportingThinker struct {
+	scriptedThinker
+	mutex   sync.Mutex
+	reports []GameReport
+}
+
+func (r *reportingThinker) GameReported(report GameReport) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+func TestNewGameReport(t *testing.T) {
+
+	gameState := MustGameStateFromFEN("B:W18,K26:BK1,5,6")
+	gameState.Number = 9
+	gameState.WinningTeam = RED_TEAM
+	gameState.Moves = []MoveHistory{
+		{Team: RED_TEAM, Turn: 1, Locations: []int{9, 13}},
+		{Team: BLUE_TEAM, Turn: 2, Locations: []int{22, 18}},
+		{Team: RED_TEAM, Turn: 3, Locations: []int{2, 6}},
+	}
+
+	report := NewGameReport(BLUE_TEAM, gameState)
+	assert.Equals(t, report.GameNumber, 9)
+	assert.Equals(t, report.Team, BLUE_TEAM)
+	assert.Equals(t, report.WinningTeam, RED_TEAM)
+	assert.Equals(t, report.MovesPlayed, 3)
+	assert.Equals(t, report.Teams[RED_TEAM].Moves, 2)
+	assert.Equals(t, report.Teams[BLUE_TEAM].Moves, 1)
+	assert.Equals(t, report.Teams[RED_TEAM].Men, 2)
+	assert.Equals(t, report.Teams[RED_TEAM].Kings, 1)
+	assert.Equals(t, report.Teams[RED_TEAM].KingsMade, 1)
+	assert.Equals(t, report.Teams[BLUE_TEAM].Men, 1)
+	assert.Equals(t, report.Teams[BLUE_TEAM].Kings, 1)
+
+	text := report.String()
+	assert.True(t, strings.HasPrefix(text, "Game #: 9 won by RED, we were BLUE\n"))
+	assert.True(t, strings.Contains(text, "Final material: RED 2 men 1 kings, BLUE 1 men 1 kings\n"))
+
+	buf := &bytes.Buffer{}
+	assert.True(t, report.WriteJSON(buf) == nil)
+	decoded := GameReport{}
+	assert.True(t, json.Unmarshal(buf.Bytes(), &decoded) == nil)
+	assert.DeepEquals(t, decoded, report)
+
+}
+
+func TestGameReportDeliveredWhenFinished(t *testing.T) {
+
+	pdnGame, err := ParsePDNGame(strings.NewReader(replayTestPDN))
+	assert.True(t, err == nil)
+	thinker := &reportingThinker{scriptedThinker: scriptedThinker{paths: map[int][]int{
+		1: {11, 15},
+		3: {15, 22},
+		5: {9, 13},
+	}}}
+
+	_, err = ReplayPDNGame(RED_TEAM, thinker, pdnGame)
+	assert.True(t, err == nil)
+
+	assert.Equals(t, len(thinker.reports), 1)
+	report := thinker.reports[0]
+	assert.Equals(t, report.GameNumber, 42)
+	assert.Equals(t, report.WinningTeam, BLUE_TEAM)
+	assert.Equals(t, report.MovesPlayed, 5)
+	assert.Equals(t, report.Teams[RED_TEAM].Captures, 1)
+	assert.Equals(t, report.Teams[BLUE_TEAM].Captures, 1)
+	assert.Equals(t, report.Teams[RED_TEAM].Men, 11)
+	assert.Equals(t, report.Teams[BLUE_TEAM].Men, 11)
+	assert.Equals(t, report.ThinkCount, 3)
+	assert.True(t, report.MaxThinkSeconds >= report.AverageThinkSeconds)
+	assert.Equals(t, report.VotesPosted, 3)
+	assert.Equals(t, report.VotedTurns, 3)
+	assert.Equals(t, report.VotesPlayed, 2)
+	assert.True(t, report.AgreementRate > 0.66 && report.AgreementRate < 0.67)
+
+}
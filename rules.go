@@ -0,0 +1,102 @@
+package checkersbot
+
+import (
+	"fmt"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+// A legal move with its full route.  Locations start with the square the
+// piece is on, and Captured has the location of every piece jumped, in
+// the order they are jumped.
+type MovePath struct {
+	Locations []int
+	Captured  []int
+	Crowned   bool
+}
+
+func (path MovePath) String() string {
+	return MoveNotation(path.Locations[0], path.Locations[1:], len(path.Captured) > 0)
+}
+
+// Returned when a move history can't be replayed
+type IllegalMoveError struct {
+	// The index of the offending move in the history
+	Index  int
+	Move   MoveHistory
+	Reason string
+}
+
+func (e *IllegalMoveError) Error() string {
+	return fmt.Sprintf("illegal move %v (%v %v): %v", e.Index+1, e.Move.Team, PDNMoveString(e.Move), e.Reason)
+}
+
+// Men move towards the opponent, RED (core BLACK) down the board and
+// BLUE (core RED) up it.  Kings go both ways.
+func pieceDirections(piece core.Piece) [][2]int {
+	switch piece {
+	case core.BLACK:
+		return [][2]int{{1, -1}, {1, 1}}
+	case core.RED:
+		return [][2]int{{-1, -1}, {-1, 1}}
+	default:
+		return [][2]int{{1, -1}, {1, 1}, {-1, -1}, {-1, 1}}
+	}
+}
+
+// Every legal move for the active team, by the rules of the game's variant
+func (gamestate GameState) LegalMovePaths() []MovePath {
+	return VariantLegalMovePaths(gamestate.ExportVariantBoard(), GetCorePlayer(gamestate.ActiveTeam))
+}
+
+// Fill in the ValidMoves of the active team's pieces from the rules, and
+// clear everyone else's.  Captures refer to the PieceIds of the jumped
+// pieces.
+func PopulateValidMoves(gameState GameState) GameState {
+
+	teams := make([]Team, len(gameState.Teams))
+	pieceIndexes := map[int][2]int{}
+	for teamIndex, team := range gameState.Teams {
+		teams[teamIndex] = team
+		teams[teamIndex].Pieces = make([]Piece, len(team.Pieces))
+		for pieceIndex, piece := range team.Pieces {
+			piece.ValidMoves = nil
+			teams[teamIndex].Pieces[pieceIndex] = piece
+			if !piece.Captured {
+				pieceIndexes[piece.Location] = [2]int{teamIndex, pieceIndex}
+			}
+		}
+	}
+	gameState.Teams = teams
+
+	if int(gameState.ActiveTeam) >= len(teams) || gameState.ActiveTeam < 0 {
+		return gameState
+	}
+	for _, path := range gameState.LegalMovePaths() {
+		validMove := ValidMove{
+			Locations: path.Locations[1:],
+			King:      path.Crowned,
+			Captures:  []Capture{},
+		}
+		for _, capturedLocation := range path.Captured {
+			index := pieceIndexes[capturedLocation]
+			validMove.Captures = append(validMove.Captures, Capture{TeamID: index[0], PieceId: index[1]})
+		}
+		index := pieceIndexes[path.Locations[0]]
+		piece := &teams[index[0]].Pieces[index[1]]
+		piece.ValidMoves = append(piece.ValidMoves, validMove)
+	}
+	return gameState
+
+}
+
+// Replay a game from the initial position and build the GameState the
+// server would have sent after the last move: pieces keep their index in
+// the starting lineup as their id, captured pieces stay in the list with
+// Captured set, and the team to move has its ValidMoves filled in.  Every
+// move has to be legal both by the local rules and by checkers-core,
+// otherwise an *IllegalMoveError for the first bad move is returned along
+// with the GameState just before it.
+func NewGameStateFromHistory(moves []MoveHistory) (gameState GameState, err error) {
+	return NewReferee(AMERICAN_CHECKERS).Replay(moves)
+}
@@ -0,0 +1,105 @@
+package checkersbot
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func validMoveKeys(validMoves []ValidMove) []string {
+	keys := []string{}
+	for _, validMove := range validMoves {
+		keys = append(keys, moveKey(validMove))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestNewGameStateFromHistory(t *testing.T) {
+
+	// the moves from the game doc used in TestIsOurTurn
+	moves := []MoveHistory{
+		{Piece: 9, Team: RED_TEAM, Locations: []int{10, 14}},
+		{Piece: 2, Team: BLUE_TEAM, Locations: []int{23, 19}},
+	}
+	gameState, err := NewGameStateFromHistory(moves)
+	assert.True(t, err == nil)
+	assert.Equals(t, gameState.ActiveTeam, RED_TEAM)
+	assert.Equals(t, gameState.WinningTeam, TeamType(-1))
+	assert.Equals(t, gameState.Turn, 3)
+	assert.Equals(t, gameState.Teams[RED_TEAM].Pieces[9].Location, 14)
+	assert.Equals(t, gameState.Teams[BLUE_TEAM].Pieces[2].Location, 19)
+
+	serverGameState := NewGameStateFromString(`{"activeTeam":0,"teams":[{"pieces":[{"location":1},{"location":2},{"location":3},{"location":4},{"location":5},{"location":6,"validMoves":[{"captures":[],"king":false,"locations":[10]}]},{"location":7,"validMoves":[{"captures":[],"king":false,"locations":[10]}]},{"location":8},{"location":9,"validMoves":[{"captures":[],"king":false,"locations":[13]}]},{"location":14,"validMoves":[{"captures":[],"king":false,"locations":[17]},{"captures":[],"king":false,"locations":[18]}]},{"location":11,"validMoves":[{"captures":[],"king":false,"locations":[15]},{"captures":[],"king":false,"locations":[16]}]},{"location":12,"validMoves":[{"captures":[],"king":false,"locations":[16]}]}]},{"pieces":[{"location":21},{"location":22},{"location":19},{"location":24},{"location":25},{"location":26},{"location":27},{"location":28},{"location":29},{"location":30},{"location":31},{"location":32}]}]}`)
+	assert.DeepEquals(t, validMoveKeys(gameState.Teams[RED_TEAM].AllValidMoves()), validMoveKeys(serverGameState.Teams[RED_TEAM].AllValidMoves()))
+
+	// blue jumps and has to be captured back
+	moves = append(moves,
+		MoveHistory{Piece: -1, Team: RED_TEAM, Locations: []int{14, 18}},
+		MoveHistory{Piece: -1, Team: BLUE_TEAM, Locations: []int{22, 15}},
+	)
+	gameState, err = NewGameStateFromHistory(moves)
+	assert.True(t, err == nil)
+	assert.True(t, gameState.Teams[RED_TEAM].Pieces[9].Captured)
+	assert.Equals(t, gameState.Moves[3].Piece, 1)
+	redMoves := gameState.Teams[RED_TEAM].AllValidMoves()
+	assert.DeepEquals(t, validMoveKeys(redMoves), []string{"11:[18]"})
+	for _, validMove := range redMoves {
+		assert.Equals(t, validMove.Captures[0].TeamID, int(BLUE_TEAM))
+		assert.Equals(t, validMove.Captures[0].PieceId, 1)
+	}
+
+}
+
+func TestNewGameStateFromHistoryIllegal(t *testing.T) {
+
+	moves := []MoveHistory{
+		{Piece: -1, Team: RED_TEAM, Locations: []int{11, 15}},
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{22, 18}},
+		{Piece: -1, Team: RED_TEAM, Locations: []int{9, 13}},
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{18, 14}},
+	}
+	gameState, err := NewGameStateFromHistory(moves)
+	illegalMoveError, ok := err.(*IllegalMoveError)
+	assert.True(t, ok)
+
+	// 9-13 ignores the compulsory jump 15x22
+	assert.Equals(t, illegalMoveError.Index, 2)
+	assert.Equals(t, len(gameState.Moves), 2)
+	assert.DeepEquals(t, validMoveKeys(gameState.Teams[RED_TEAM].AllValidMoves()), []string{"15:[22]"})
+
+	_, err = NewGameStateFromHistory([]MoveHistory{{Piece: -1, Team: BLUE_TEAM, Locations: []int{23, 19}}})
+	assert.True(t, err != nil)
+
+	_, err = NewGameStateFromHistory([]MoveHistory{{Piece: 3, Team: RED_TEAM, Locations: []int{10, 14}}})
+	assert.True(t, err != nil)
+
+}
+
+func TestLegalMovePathsMultiJump(t *testing.T) {
+
+	// the king on 27 can take 23 and then either 14 or 15
+	paths := MustGameStateFromFEN("W:WK27:B14,15,23").LegalMovePaths()
+	assert.Equals(t, len(paths), 2)
+	assert.DeepEquals(t, paths[0].Locations, []int{27, 18, 9})
+	assert.DeepEquals(t, paths[0].Captured, []int{23, 14})
+	assert.DeepEquals(t, paths[1].Locations, []int{27, 18, 11})
+
+	// crowning ends the move, even though a king could jump on from 31
+	gameState := MustGameStateFromFEN("B:W26,27:B22")
+	assert.Equals(t, gameState.ActiveTeam, RED_TEAM)
+	paths = gameState.LegalMovePaths()
+	assert.Equals(t, len(paths), 1)
+	assert.DeepEquals(t, paths[0].Locations, []int{22, 31})
+	assert.True(t, paths[0].Crowned)
+
+}
+
+func TestGameStateFromHistoryFinished(t *testing.T) {
+
+	gameState := PopulateValidMoves(MustGameStateFromFEN("W:W18:B14,K1"))
+	assert.Equals(t, len(gameState.Teams[BLUE_TEAM].AllValidMoves()), 1)
+	assert.Equals(t, len(gameState.Teams[RED_TEAM].AllValidMoves()), 0)
+
+}
@@ -0,0 +1,116 @@
+package checkersbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// A snapshot of what a running Game is up to, served on /status
+type Status struct {
+	Team             string         `json:"team"`
+	UserId           string         `json:"userId"`
+	GameNumber       int            `json:"gameNumber"`
+	Turn             int            `json:"turn"`
+	ActiveTeam       string         `json:"activeTeam"`
+	IsThinking       bool           `json:"isThinking"`
+	LastVote         *OutgoingVotes `json:"lastVote,omitempty"`
+	LastVoteRev      string         `json:"lastVoteRev,omitempty"`
+	LastVoteTime     time.Time      `json:"lastVoteTime"`
+	LastFeedActivity time.Time      `json:"lastFeedActivity"`
+	ConnectionLost   bool           `json:"connectionLost"`
+	LastError        string         `json:"lastError,omitempty"`
+	LastErrorTime    time.Time      `json:"lastErrorTime"`
+	Influence        InfluenceStats `json:"influence"`
+}
+
+// Healthy as long as the changes feed hasn't gone quiet
+func (status Status) Healthy() bool {
+	return !status.ConnectionLost
+}
+
+func (game *Game) Status() Status {
+
+	status := Status{Team: game.ourTeamId.String()}
+
+	game.statusMutex.Lock()
+	status.UserId = game.statusUserId
+	status.GameNumber = game.statusGameNumber
+	status.Turn = game.statusTurn
+	status.ActiveTeam = game.statusActiveTeam.String()
+	if game.lastVote != nil {
+		lastVote := *game.lastVote
+		status.LastVote = &lastVote
+	}
+	status.LastVoteRev = game.lastVoteRev
+	status.LastVoteTime = game.lastVoteTime
+	if game.lastError != nil {
+		status.LastError = game.lastError.Error()
+	}
+	status.LastErrorTime = game.lastErrorTime
+	game.statusMutex.Unlock()
+
+	game.isThinkingMutex.Lock()
+	status.IsThinking = game.isThinking
+	game.isThinkingMutex.Unlock()
+
+	game.feedMutex.Lock()
+	status.LastFeedActivity = game.lastFeedActivity
+	status.ConnectionLost = game.connectionLost
+	game.feedMutex.Unlock()
+
+	status.Influence = game.Influence()
+
+	return status
+
+}
+
+// The HTTP handlers read these from their own goroutines, so the game
+// loop records what they need here rather than them reading the Game.
+func (game *Game) recordGameState(gameState GameState) {
+	game.statusMutex.Lock()
+	defer game.statusMutex.Unlock()
+	game.statusGameNumber = gameState.Number
+	game.statusTurn = gameState.Turn
+	game.statusActiveTeam = gameState.ActiveTeam
+	game.statusRev = gameState.Rev
+}
+
+func (game *Game) recordUser(user User) {
+	game.statusMutex.Lock()
+	defer game.statusMutex.Unlock()
+	game.statusUserId = user.Id
+}
+
+func (game *Game) recordVote(votes OutgoingVotes, rev string) {
+	game.statusMutex.Lock()
+	defer game.statusMutex.Unlock()
+	game.lastVote = &votes
+	game.lastVoteRev = rev
+	game.lastVoteTime = time.Now()
+}
+
+func (game *Game) recordError(err error) {
+	game.statusMutex.Lock()
+	defer game.statusMutex.Unlock()
+	game.lastError = err
+	game.lastErrorTime = time.Now()
+}
+
+func (game *Game) serveHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	if !game.Status().Healthy() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "changes feed lost")
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (game *Game) serveStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(game.Status())
+}
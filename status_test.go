@@ -0,0 +1,65 @@
+package checkersbot
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+func TestStatus(t *testing.T) {
+
+	game := NewGame(BLUE_TEAM, nil)
+	game.recordUser(User{Id: "user:1234"})
+	game.recordGameState(GameState{Number: 7, Turn: 4, ActiveTeam: BLUE_TEAM})
+	game.recordVote(OutgoingVotes{Id: "vote:user:1234", Turn: 4, Locations: []int{22, 18}}, "3-abc")
+	game.recordError(errors.New("409 conflict"))
+	game.feedActivity()
+
+	status := game.Status()
+	assert.Equals(t, status.Team, "BLUE")
+	assert.Equals(t, status.UserId, "user:1234")
+	assert.Equals(t, status.GameNumber, 7)
+	assert.Equals(t, status.Turn, 4)
+	assert.Equals(t, status.ActiveTeam, "BLUE")
+	assert.False(t, status.IsThinking)
+	assert.DeepEquals(t, status.LastVote.Locations, []int{22, 18})
+	assert.Equals(t, status.LastVoteRev, "3-abc")
+	assert.Equals(t, status.LastError, "409 conflict")
+	assert.False(t, status.LastFeedActivity.IsZero())
+	assert.True(t, status.Healthy())
+
+}
+
+func TestMonitoringHandlerHealth(t *testing.T) {
+
+	game := NewGame(RED_TEAM, nil)
+	game.SetFeedTimeout(time.Minute)
+	server := httptest.NewServer(game.MonitoringHandler())
+	defer server.Close()
+
+	response, err := server.Client().Get(server.URL + "/healthz")
+	assert.True(t, err == nil)
+	response.Body.Close()
+	assert.Equals(t, response.StatusCode, http.StatusOK)
+
+	game.feedActivity()
+	game.checkFeed(time.Now().Add(2 * time.Minute))
+	response, err = server.Client().Get(server.URL + "/healthz")
+	assert.True(t, err == nil)
+	response.Body.Close()
+	assert.Equals(t, response.StatusCode, http.StatusServiceUnavailable)
+
+	response, err = server.Client().Get(server.URL + "/status")
+	assert.True(t, err == nil)
+	defer response.Body.Close()
+	status := Status{}
+	assert.True(t, json.NewDecoder(response.Body).Decode(&status) == nil)
+	assert.Equals(t, status.Team, "RED")
+	assert.True(t, status.ConnectionLost)
+
+}
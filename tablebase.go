@@ -0,0 +1,367 @@
+package checkersbot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/couchbaselabs/logg"
+	core "github.com/tleyden/checkers-core"
+)
+
+// Positions are packed into a uint64 key, 7 bits per piece (5 bits of
+// square, 2 bits of piece type), so up to 8 pieces would fit in a key, but
+// generation keeps every position it enumerates in memory and from 5
+// pieces on that runs to gigabytes.
+const MAX_TABLEBASE_PIECES = 4
+
+const (
+	tablebaseMagic   = "CBTB"
+	tablebaseVersion = 1
+)
+
+type TablebaseResult int
+
+// Results are always from the point of view of the side to move
+const (
+	TB_UNKNOWN = TablebaseResult(iota)
+	TB_WIN
+	TB_LOSS
+	TB_DRAW
+)
+
+func (r TablebaseResult) String() string {
+	switch r {
+	case TB_WIN:
+		return "WIN"
+	case TB_LOSS:
+		return "LOSS"
+	case TB_DRAW:
+		return "DRAW"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// An endgame database covering every position with up to MaxPieces
+// pieces on the board.  Only decisive positions are stored, sorted by
+// key, anything else within the piece limit is a draw.
+type Tablebase struct {
+	MaxPieces int
+	keys      []uint64
+	values    []uint16
+}
+
+type tablebaseNode struct {
+	key        uint64
+	successors []int32 // index into nodes, or -1 for "opponent has no pieces left"
+	result     TablebaseResult
+	depth      int
+}
+
+// Generate a tablebase for all positions with up to maxPieces pieces,
+// working backwards from the terminal positions.  On pass N every
+// position that can reach a loss resolved on an earlier pass is a win in
+// N plies, and every position whose moves all lead to resolved wins is a
+// loss in N plies.  Whatever is left when a pass changes nothing is a draw.
+func GenerateTablebase(maxPieces int) (*Tablebase, error) {
+
+	if maxPieces < 2 || maxPieces > MAX_TABLEBASE_PIECES {
+		return nil, fmt.Errorf("maxPieces must be between 2 and %v, got %v", MAX_TABLEBASE_PIECES, maxPieces)
+	}
+
+	nodes := []tablebaseNode{}
+	index := map[uint64]int32{}
+	boards := []core.Board{}
+	players := []core.Player{}
+
+	enumerateTablebasePositions(maxPieces, func(board core.Board) {
+		for _, player := range []core.Player{core.BLACK_PLAYER, core.RED_PLAYER} {
+			key := tablebaseKey(board, player)
+			index[key] = int32(len(nodes))
+			nodes = append(nodes, tablebaseNode{key: key})
+			boards = append(boards, board)
+			players = append(players, player)
+		}
+	})
+	logg.LogTo("CHECKERSBOT", "Tablebase: enumerated %v positions with up to %v pieces", len(nodes), maxPieces)
+
+	for i := range nodes {
+		player := players[i]
+		opponent := opponentCorePlayer(player)
+		for _, move := range boards[i].LegalMoves(player) {
+			nextBoard := boards[i].ApplyMove(player, move)
+			if countCorePieces(nextBoard, opponent) == 0 {
+				nodes[i].successors = append(nodes[i].successors, -1)
+				continue
+			}
+			successor, ok := index[tablebaseKey(nextBoard, opponent)]
+			if !ok {
+				return nil, fmt.Errorf("successor of position %x missing from tablebase", nodes[i].key)
+			}
+			nodes[i].successors = append(nodes[i].successors, successor)
+		}
+		if len(nodes[i].successors) == 0 {
+			nodes[i].result = TB_LOSS
+		}
+	}
+
+	for pass := 1; ; pass++ {
+		resolved := []int{}
+		results := []TablebaseResult{}
+		for i, node := range nodes {
+			if node.result != TB_UNKNOWN {
+				continue
+			}
+			allWins := true
+			for _, successor := range node.successors {
+				result := TB_LOSS
+				if successor >= 0 {
+					result = nodes[successor].result
+				}
+				if result == TB_LOSS {
+					allWins = false
+					resolved = append(resolved, i)
+					results = append(results, TB_WIN)
+					break
+				}
+				if result != TB_WIN {
+					allWins = false
+				}
+			}
+			if allWins {
+				resolved = append(resolved, i)
+				results = append(results, TB_LOSS)
+			}
+		}
+		if len(resolved) == 0 {
+			break
+		}
+
+		// apply afterwards so that a pass only sees results from earlier
+		// passes, which keeps the depth equal to the shortest win
+		for j, i := range resolved {
+			nodes[i].result = results[j]
+			nodes[i].depth = pass
+		}
+		logg.LogTo("CHECKERSBOT", "Tablebase: pass %v resolved %v positions", pass, len(resolved))
+	}
+
+	tablebase := &Tablebase{MaxPieces: maxPieces}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].key < nodes[j].key })
+	for _, node := range nodes {
+		if node.result != TB_WIN && node.result != TB_LOSS {
+			continue
+		}
+		tablebase.keys = append(tablebase.keys, node.key)
+		tablebase.values = append(tablebase.values, packTablebaseValue(node.result, node.depth))
+	}
+	return tablebase, nil
+
+}
+
+// Look up a position.  ok is false if the position has more pieces than
+// the tablebase covers.  The depth is the number of plies until the game
+// is decided with best play, and is zero for draws.
+func (tablebase *Tablebase) Probe(board core.Board, player core.Player) (result TablebaseResult, depth int, ok bool) {
+
+	pieceCount := countCorePieces(board, core.BLACK_PLAYER) + countCorePieces(board, core.RED_PLAYER)
+	if pieceCount > tablebase.MaxPieces {
+		return TB_UNKNOWN, 0, false
+	}
+	if countCorePieces(board, player) == 0 {
+		return TB_LOSS, 0, true
+	}
+
+	key := tablebaseKey(board, player)
+	i := sort.Search(len(tablebase.keys), func(i int) bool { return tablebase.keys[i] >= key })
+	if i < len(tablebase.keys) && tablebase.keys[i] == key {
+		result, depth = unpackTablebaseValue(tablebase.values[i])
+		return result, depth, true
+	}
+	return TB_DRAW, 0, true
+
+}
+
+// The number of decisive positions stored
+func (tablebase *Tablebase) Len() int {
+	return len(tablebase.keys)
+}
+
+// Write the tablebase in its on-disk format: a "CBTB" magic, a version
+// byte, the piece limit byte and an entry count, followed by the sorted
+// entries as a uint64 key and a uint16 result/depth, all big endian.
+func (tablebase *Tablebase) WriteTo(w io.Writer) (n int64, err error) {
+
+	bufWriter := bufio.NewWriter(w)
+	header := make([]byte, 10)
+	copy(header[0:4], tablebaseMagic)
+	header[4] = tablebaseVersion
+	header[5] = byte(tablebase.MaxPieces)
+	binary.BigEndian.PutUint32(header[6:10], uint32(len(tablebase.keys)))
+	written, err := bufWriter.Write(header)
+	n += int64(written)
+	if err != nil {
+		return
+	}
+
+	entry := make([]byte, 10)
+	for i, key := range tablebase.keys {
+		binary.BigEndian.PutUint64(entry[0:8], key)
+		binary.BigEndian.PutUint16(entry[8:10], tablebase.values[i])
+		written, err = bufWriter.Write(entry)
+		n += int64(written)
+		if err != nil {
+			return
+		}
+	}
+	err = bufWriter.Flush()
+	return
+
+}
+
+func ReadTablebase(r io.Reader) (*Tablebase, error) {
+
+	bufReader := bufio.NewReader(r)
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(bufReader, header); err != nil {
+		return nil, err
+	}
+	if string(header[0:4]) != tablebaseMagic {
+		return nil, fmt.Errorf("not a tablebase file, bad magic %q", header[0:4])
+	}
+	if header[4] != tablebaseVersion {
+		return nil, fmt.Errorf("unsupported tablebase version %v", header[4])
+	}
+
+	tablebase := &Tablebase{MaxPieces: int(header[5])}
+	count := binary.BigEndian.Uint32(header[6:10])
+	tablebase.keys = make([]uint64, count)
+	tablebase.values = make([]uint16, count)
+
+	entry := make([]byte, 10)
+	for i := range tablebase.keys {
+		if _, err := io.ReadFull(bufReader, entry); err != nil {
+			return nil, err
+		}
+		tablebase.keys[i] = binary.BigEndian.Uint64(entry[0:8])
+		tablebase.values[i] = binary.BigEndian.Uint16(entry[8:10])
+	}
+	return tablebase, nil
+
+}
+
+func LoadTablebase(path string) (*Tablebase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadTablebase(file)
+}
+
+func (tablebase *Tablebase) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err = tablebase.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Call the callback with every board that has between 2 and maxPieces
+// pieces and at least one piece per side.  Men are never placed on the
+// row where they would have been crowned.
+func enumerateTablebasePositions(maxPieces int, callback func(board core.Board)) {
+
+	pieceTypes := []core.Piece{core.BLACK, core.BLACK_KING, core.RED, core.RED_KING}
+
+	var place func(board core.Board, nextLocation int, placed int)
+	place = func(board core.Board, nextLocation int, placed int) {
+		if placed >= 2 && countCorePieces(board, core.BLACK_PLAYER) > 0 && countCorePieces(board, core.RED_PLAYER) > 0 {
+			callback(board)
+		}
+		if placed == maxPieces {
+			return
+		}
+		for location := nextLocation; location <= 32; location++ {
+			loc := GetCoreLocation(location)
+			for _, piece := range pieceTypes {
+				if piece == core.BLACK && loc.Row() == 7 {
+					continue
+				}
+				if piece == core.RED && loc.Row() == 0 {
+					continue
+				}
+				board[loc.Row()][loc.Col()] = piece
+				place(board, location+1, placed+1)
+			}
+			board[loc.Row()][loc.Col()] = core.EMPTY
+		}
+	}
+	place(core.NewEmptyBoard(), 1, 0)
+
+}
+
+// Pack a position into a key: bits 0-55 hold up to 8 pieces in square
+// order, bits 56-59 the piece count and bit 60 the side to move.
+func tablebaseKey(board core.Board, player core.Player) uint64 {
+	var key uint64
+	count := 0
+	for location := 1; location <= 32; location++ {
+		loc := GetCoreLocation(location)
+		piece := board.PieceAt(loc)
+		if piece == core.EMPTY {
+			continue
+		}
+		pieceCode := uint64(piece - core.BLACK)
+		key |= (uint64(location-1)<<2 | pieceCode) << uint(7*count)
+		count++
+	}
+	key |= uint64(count) << 56
+	if player == core.RED_PLAYER {
+		key |= 1 << 60
+	}
+	return key
+}
+
+func packTablebaseValue(result TablebaseResult, depth int) uint16 {
+	return uint16(result)<<14 | uint16(depth&0x3fff)
+}
+
+func unpackTablebaseValue(value uint16) (result TablebaseResult, depth int) {
+	return TablebaseResult(value >> 14), int(value & 0x3fff)
+}
+
+func countCorePieces(board core.Board, player core.Player) (count int) {
+	for location := 1; location <= 32; location++ {
+		if corePieceOwnedBy(board.PieceAt(GetCoreLocation(location)), player) {
+			count++
+		}
+	}
+	return
+}
+
+func corePieceOwnedBy(piece core.Piece, player core.Player) bool {
+	switch player {
+	case core.BLACK_PLAYER:
+		return piece == core.BLACK || piece == core.BLACK_KING
+	default:
+		return piece == core.RED || piece == core.RED_KING
+	}
+}
+
+func opponentCorePlayer(player core.Player) core.Player {
+	switch player {
+	case core.BLACK_PLAYER:
+		return core.RED_PLAYER
+	default:
+		return core.BLACK_PLAYER
+	}
+}
@@ -0,0 +1,103 @@
+package checkersbot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+func TestTablebaseProbe(t *testing.T) {
+
+	tablebase, err := GenerateTablebase(2)
+	assert.True(t, err == nil)
+	assert.True(t, tablebase.Len() > 0)
+
+	// black man about to jump the lone red man
+	board := core.NewEmptyBoard()
+	board[2][1] = core.BLACK
+	board[3][2] = core.RED
+	result, depth, ok := tablebase.Probe(board, core.BLACK_PLAYER)
+	assert.True(t, ok)
+	assert.Equals(t, result, TB_WIN)
+	assert.Equals(t, depth, 1)
+
+	// same position with red to move, red jumps first
+	result, depth, ok = tablebase.Probe(board, core.RED_PLAYER)
+	assert.True(t, ok)
+	assert.Equals(t, result, TB_WIN)
+	assert.Equals(t, depth, 1)
+
+	// too many pieces for this tablebase
+	board[7][0] = core.RED_KING
+	_, _, ok = tablebase.Probe(board, core.BLACK_PLAYER)
+	assert.False(t, ok)
+
+}
+
+func TestTablebaseRoundTrip(t *testing.T) {
+
+	tablebase, err := GenerateTablebase(2)
+	assert.True(t, err == nil)
+
+	buf := &bytes.Buffer{}
+	_, err = tablebase.WriteTo(buf)
+	assert.True(t, err == nil)
+	assert.Equals(t, buf.Len(), 10+10*tablebase.Len())
+
+	loaded, err := ReadTablebase(buf)
+	assert.True(t, err == nil)
+	assert.Equals(t, loaded.MaxPieces, 2)
+	assert.Equals(t, loaded.Len(), tablebase.Len())
+	for i := range tablebase.keys {
+		assert.Equals(t, loaded.keys[i], tablebase.keys[i])
+		assert.Equals(t, loaded.values[i], tablebase.values[i])
+	}
+
+	_, err = ReadTablebase(bytes.NewBufferString("nope, not a tablebase"))
+	assert.True(t, err != nil)
+
+}
+
+type fixedThinker struct {
+	move ValidMove
+}
+
+func (f fixedThinker) Think(gameState GameState) (ValidMove, bool) {
+	return f.move, true
+}
+
+func TestTablebaseThinker(t *testing.T) {
+
+	tablebase, err := GenerateTablebase(2)
+	assert.True(t, err == nil)
+
+	inner := fixedThinker{move: ValidMove{StartLocation: 99}}
+	thinker := NewTablebaseThinker(tablebase, inner)
+
+	// red man on 9 can jump the blue man on 14
+	gameState := GameState{
+		ActiveTeam:  RED_TEAM,
+		WinningTeam: -1,
+		Teams: []Team{
+			{Pieces: []Piece{
+				{Location: 9, ValidMoves: []ValidMove{{Locations: []int{18}, Captures: []Capture{{TeamID: 1, PieceId: 0}}}}},
+			}},
+			{Pieces: []Piece{
+				{Location: 14},
+			}},
+		},
+	}
+	validMove, ok := thinker.Think(gameState)
+	assert.True(t, ok)
+	assert.Equals(t, validMove.StartLocation, 9)
+	assert.Equals(t, validMove.EndLocation(), 18)
+
+	// too many pieces, defer to the inner thinker
+	gameState.Teams[1].Pieces = append(gameState.Teams[1].Pieces, Piece{Location: 30})
+	validMove, ok = thinker.Think(gameState)
+	assert.True(t, ok)
+	assert.Equals(t, validMove.StartLocation, 99)
+
+}
@@ -0,0 +1,95 @@
+package checkersbot
+
+import (
+	"github.com/couchbaselabs/logg"
+)
+
+// A Thinker that plays perfectly out of an endgame tablebase once few
+// enough pieces are left on the board, and defers to the Inner thinker
+// the rest of the time.
+type TablebaseThinker struct {
+	Tablebase *Tablebase
+	Inner     Thinker
+}
+
+func NewTablebaseThinker(tablebase *Tablebase, inner Thinker) *TablebaseThinker {
+	return &TablebaseThinker{Tablebase: tablebase, Inner: inner}
+}
+
+func (t *TablebaseThinker) Think(gameState GameState) (validMove ValidMove, ok bool) {
+
+	// the tablebase is for the standard 8x8 game
+	if gameState.PieceCount() > t.Tablebase.MaxPieces || gameState.Objective != STANDARD_OBJECTIVE || gameState.Variant != AMERICAN_CHECKERS {
+		return t.Inner.Think(gameState)
+	}
+
+	validMove, ok = t.probeBestMove(gameState)
+	if !ok {
+		logg.LogTo("CHECKERSBOT", "Tablebase probe failed, falling back to inner thinker")
+		return t.Inner.Think(gameState)
+	}
+	return
+
+}
+
+// Pass GameFinished through to the inner thinker, since Game only calls
+// it on the thinker it was given
+func (t *TablebaseThinker) GameFinished(gameState GameState) (shouldQuit bool) {
+	if observer, ok := t.Inner.(Observer); ok {
+		return observer.GameFinished(gameState)
+	}
+	return false
+}
+
+// Pick the move that leads to the fastest win, failing that a draw, and
+// failing that the slowest loss.  Each route is played out by the Referee
+// and matched to the valid move with the same captures, so capture
+// sequences that end on the same square can't be mixed up.
+func (t *TablebaseThinker) probeBestMove(gameState GameState) (validMove ValidMove, ok bool) {
+
+	referee := NewReferee(AMERICAN_CHECKERS)
+	opponent := opponentCorePlayer(GetCorePlayer(gameState.ActiveTeam))
+	allValidMoves := gameState.Teams[gameState.ActiveTeam].AllValidMoves()
+
+	bestScore := 0
+	found := false
+	var bestPath MovePath
+	for _, path := range gameState.LegalMovePaths() {
+		next, err := referee.ApplyMove(gameState, MoveHistory{Piece: -1, Team: gameState.ActiveTeam, Locations: path.Locations})
+		if err != nil {
+			logg.LogTo("CHECKERSBOT", "Tablebase could not play %v: %v", path, err)
+			return
+		}
+		result, depth, probed := t.Tablebase.Probe(next.Export(), opponent)
+		if !probed {
+			return
+		}
+
+		// the result is from the opponent's point of view
+		var score int
+		switch result {
+		case TB_LOSS:
+			score = 100000 - depth
+		case TB_DRAW:
+			score = 0
+		default:
+			score = -100000 + depth
+		}
+		if !found || score > bestScore {
+			found = true
+			bestScore = score
+			bestPath = path
+		}
+	}
+	if !found {
+		return
+	}
+
+	index, err := FindValidMovePath(gameState, bestPath, allValidMoves)
+	if err != nil {
+		logg.LogTo("CHECKERSBOT", "Tablebase move not among valid moves %v: %v", allValidMoves, err)
+		return
+	}
+	return allValidMoves[index], true
+
+}
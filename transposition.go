@@ -0,0 +1,185 @@
+package checkersbot
+
+import (
+	"sync"
+	"sync/atomic"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+type TTBound int
+
+const (
+	TT_EXACT = TTBound(iota)
+	TT_LOWER_BOUND
+	TT_UPPER_BOUND
+)
+
+type ReplacementScheme int
+
+const (
+	// Every store overwrites whatever was in the slot
+	REPLACE_ALWAYS = ReplacementScheme(iota)
+
+	// Keep the deeper search result, unless the existing entry was stored
+	// during an earlier search (see NewSearch) and is now stale
+	REPLACE_DEPTH_PREFERRED
+
+	// Each bucket has a depth-preferred slot and an always-replace slot,
+	// so shallow results near the leaves don't evict deep ones
+	REPLACE_TWO_TIER
+)
+
+// The number of locks guarding the table.  Buckets are striped across
+// them so concurrent searches rarely contend.
+const transpositionTableLocks = 256
+
+type TTEntry struct {
+	Hash     uint64
+	Depth    int
+	Score    int
+	Bound    TTBound
+	BestMove core.Move
+	HasMove  bool
+
+	generation uint32
+	used       bool
+}
+
+// A fixed size hash table of search results keyed by Zobrist hash, safe
+// for concurrent use by several searches.  One table can be kept for the
+// lifetime of a GameLoop and shared across turns, call NewSearch at the
+// start of each turn so the replacement scheme can age out old entries.
+type TranspositionTable struct {
+	entries    []TTEntry
+	mask       uint64
+	scheme     ReplacementScheme
+	generation uint32
+	locks      [transpositionTableLocks]sync.Mutex
+	hits       uint64
+	misses     uint64
+}
+
+// Create a table holding at most maxEntries entries, rounded down to a
+// power of two.
+func NewTranspositionTable(maxEntries int, scheme ReplacementScheme) *TranspositionTable {
+	size := 2
+	for size*2 <= maxEntries {
+		size *= 2
+	}
+	return &TranspositionTable{
+		entries: make([]TTEntry, size),
+		mask:    uint64(size - 1),
+		scheme:  scheme,
+	}
+}
+
+// The slots a hash can live in.  With the two tier scheme a hash maps
+// to a pair of adjacent slots, the first one being depth-preferred.
+func (tt *TranspositionTable) slots(hash uint64) (first, last uint64) {
+	first = hash & tt.mask
+	if tt.scheme == REPLACE_TWO_TIER {
+		first = first &^ 1
+		return first, first + 1
+	}
+	return first, first
+}
+
+func (tt *TranspositionTable) lockFor(slot uint64) *sync.Mutex {
+	// both slots of a two tier bucket share a lock
+	return &tt.locks[(slot>>1)%transpositionTableLocks]
+}
+
+func (tt *TranspositionTable) Probe(hash uint64) (entry TTEntry, found bool) {
+	first, last := tt.slots(hash)
+	lock := tt.lockFor(first)
+	lock.Lock()
+	for slot := first; slot <= last; slot++ {
+		if tt.entries[slot].used && tt.entries[slot].Hash == hash {
+			entry = tt.entries[slot]
+			found = true
+			break
+		}
+	}
+	lock.Unlock()
+
+	if found {
+		atomic.AddUint64(&tt.hits, 1)
+	} else {
+		atomic.AddUint64(&tt.misses, 1)
+	}
+	return
+}
+
+func (tt *TranspositionTable) Store(entry TTEntry) {
+	entry.generation = atomic.LoadUint32(&tt.generation)
+	entry.used = true
+
+	first, last := tt.slots(entry.Hash)
+	lock := tt.lockFor(first)
+	lock.Lock()
+	defer lock.Unlock()
+
+	switch tt.scheme {
+	case REPLACE_ALWAYS:
+		tt.entries[first] = entry
+	case REPLACE_DEPTH_PREFERRED:
+		if tt.shouldReplace(tt.entries[first], entry) {
+			tt.entries[first] = entry
+		}
+	default:
+		deep := &tt.entries[first]
+		if tt.shouldReplace(*deep, entry) {
+			// the evicted deep entry still gets a second chance
+			// in the always-replace slot
+			if deep.used && deep.Hash != entry.Hash {
+				tt.entries[last] = *deep
+			}
+			*deep = entry
+		} else {
+			tt.entries[last] = entry
+		}
+	}
+}
+
+func (tt *TranspositionTable) shouldReplace(existing, entry TTEntry) bool {
+	switch {
+	case !existing.used:
+		return true
+	case existing.Hash == entry.Hash:
+		return entry.Depth >= existing.Depth || entry.Bound == TT_EXACT
+	case existing.generation != entry.generation:
+		return true
+	default:
+		return entry.Depth >= existing.Depth
+	}
+}
+
+// Mark the start of a new search, eg, a new turn.  Entries from earlier
+// searches stay probeable but lose their protection from replacement.
+func (tt *TranspositionTable) NewSearch() {
+	atomic.AddUint32(&tt.generation, 1)
+}
+
+func (tt *TranspositionTable) Clear() {
+	for i := range tt.locks {
+		tt.locks[i].Lock()
+	}
+	for i := range tt.entries {
+		tt.entries[i] = TTEntry{}
+	}
+	for i := range tt.locks {
+		tt.locks[i].Unlock()
+	}
+	atomic.StoreUint64(&tt.hits, 0)
+	atomic.StoreUint64(&tt.misses, 0)
+}
+
+// The maximum number of entries the table can hold
+func (tt *TranspositionTable) Capacity() int {
+	return len(tt.entries)
+}
+
+func (tt *TranspositionTable) Stats() (hits, misses uint64) {
+	return atomic.LoadUint64(&tt.hits), atomic.LoadUint64(&tt.misses)
+}
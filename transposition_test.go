@@ -0,0 +1,132 @@
+package checkersbot
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+func TestZobristIncrementalHash(t *testing.T) {
+
+	gameState := NewGameStateFromString(SampleJson())
+	board := gameState.Export()
+	hasher := NewZobristHasher(42)
+
+	player := core.BLACK_PLAYER
+	hash := hasher.Hash(board, player)
+	assert.NotEquals(t, hash, hasher.Hash(board, core.RED_PLAYER))
+
+	// play a few moves, the incremental hash should always
+	// agree with hashing the resulting board from scratch
+	for i := 0; i < 10; i++ {
+		moves := board.LegalMoves(player)
+		if len(moves) == 0 {
+			break
+		}
+		board, hash = hasher.ApplyMove(board, hash, player, moves[0])
+		player = opponentCorePlayer(player)
+		assert.Equals(t, hash, hasher.Hash(board, player))
+	}
+
+}
+
+func TestZobristIncrementalHashJumps(t *testing.T) {
+
+	hasher := NewZobristHasher(7)
+
+	// a double jump, two jumps ending on the same square, a king's jumps
+	// and a man jumping onto the back rank
+	for _, fen := range []string{"B:W6,7,14,15:B2", "B:W26,27:B22", "W:WK18:B14,15,22,23", "W:W10:B6"} {
+		board, player, err := BoardFromFEN(fen)
+		assert.True(t, err == nil)
+		hash := hasher.Hash(board, player)
+		for _, move := range board.LegalMoves(player) {
+			nextBoard, nextHash := hasher.ApplyMove(board, hash, player, move)
+			assert.Equals(t, nextHash, hasher.Hash(nextBoard, opponentCorePlayer(player)))
+		}
+	}
+
+	board, player, _ := BoardFromFEN("W:W10:B6")
+	move := board.LegalMoves(player)[0]
+	nextBoard, _ := hasher.ApplyMove(board, hasher.Hash(board, player), player, move)
+	assert.Equals(t, nextBoard.PieceAt(move.To()), core.RED_KING)
+
+	// a "jump" over nothing can't be worked out, so it's hashed in full
+	board, player, _ = BoardFromFEN("B:W30:B18")
+	move = core.NewMoveFromTo(GetCoreLocation(18), GetCoreLocation(25))
+	nextBoard, nextHash := hasher.ApplyMove(board, hasher.Hash(board, player), player, move)
+	assert.Equals(t, nextHash, hasher.Hash(nextBoard, opponentCorePlayer(player)))
+
+}
+
+func TestTranspositionTableDepthPreferred(t *testing.T) {
+
+	tt := NewTranspositionTable(1024, REPLACE_DEPTH_PREFERRED)
+	assert.Equals(t, tt.Capacity(), 1024)
+
+	tt.Store(TTEntry{Hash: 5, Depth: 6, Score: 10})
+	entry, found := tt.Probe(5)
+	assert.True(t, found)
+	assert.Equals(t, entry.Score, 10)
+
+	// same slot, shallower search: keep the deep entry
+	colliding := uint64(5 + 1024)
+	tt.Store(TTEntry{Hash: colliding, Depth: 2, Score: 20})
+	_, found = tt.Probe(colliding)
+	assert.False(t, found)
+
+	// after a new search the old entry is fair game
+	tt.NewSearch()
+	tt.Store(TTEntry{Hash: colliding, Depth: 2, Score: 20})
+	entry, found = tt.Probe(colliding)
+	assert.True(t, found)
+	assert.Equals(t, entry.Score, 20)
+
+	hits, misses := tt.Stats()
+	assert.Equals(t, hits, uint64(2))
+	assert.Equals(t, misses, uint64(1))
+
+}
+
+func TestTranspositionTableTwoTier(t *testing.T) {
+
+	tt := NewTranspositionTable(16, REPLACE_TWO_TIER)
+	tt.Store(TTEntry{Hash: 2, Depth: 8})
+	tt.Store(TTEntry{Hash: 3, Depth: 1})
+	tt.Store(TTEntry{Hash: 19, Depth: 1})
+
+	// the deep entry survives, the latest shallow one
+	// replaced the earlier shallow one
+	_, found := tt.Probe(2)
+	assert.True(t, found)
+	_, found = tt.Probe(3)
+	assert.False(t, found)
+	_, found = tt.Probe(19)
+	assert.True(t, found)
+
+	tt.Clear()
+	_, found = tt.Probe(2)
+	assert.False(t, found)
+
+}
+
+func TestTranspositionTableConcurrent(t *testing.T) {
+
+	tt := NewTranspositionTable(4096, REPLACE_ALWAYS)
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				hash := uint64(worker*1000 + i)
+				tt.Store(TTEntry{Hash: hash, Score: i})
+				tt.Probe(hash)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+}
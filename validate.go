@@ -0,0 +1,203 @@
+package checkersbot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+// What the Game does when the server's validMoves disagree with the rules
+type ValidMovesCheck int
+
+const (
+	// Don't check, trust the server
+	IGNORE_VALID_MOVES_MISMATCH = ValidMovesCheck(iota)
+
+	// Check and log any differences, but vote as usual
+	LOG_VALID_MOVES_MISMATCH
+
+	// Check, log, and don't call the thinker or vote for that revision
+	REFUSE_VALID_MOVES_MISMATCH
+)
+
+// A move the server and the rules agree on the route of, but not on what
+// it captures or whether it crowns.
+type MoveMismatch struct {
+	ValidMove ValidMove
+	Path      MovePath
+	Reason    string
+}
+
+// The differences between the validMoves the server sent for the active
+// team and the moves the rules allow.
+type ValidMovesDiff struct {
+
+	// Legal by the rules, but not offered by the server
+	Missing []MovePath
+
+	// Offered by the server, but not a legal route
+	Unexpected []ValidMove
+
+	// Same route, different captures or crowning
+	Mismatched []MoveMismatch
+
+	// checkers-core only knows the start and end of a move, so these are
+	// compared with EqualsCoreMove: legal core moves that no valid move
+	// matches, and valid moves that match no legal core move.
+	CoreMissing    []core.Move
+	CoreUnexpected []ValidMove
+}
+
+func (diff ValidMovesDiff) Empty() bool {
+	return len(diff.Missing) == 0 && len(diff.Unexpected) == 0 && len(diff.Mismatched) == 0 &&
+		len(diff.CoreMissing) == 0 && len(diff.CoreUnexpected) == 0
+}
+
+func (diff ValidMovesDiff) String() string {
+	if diff.Empty() {
+		return "valid moves match the rules"
+	}
+	lines := []string{}
+	for _, path := range diff.Missing {
+		lines = append(lines, fmt.Sprintf("missing: %v", path))
+	}
+	for _, validMove := range diff.Unexpected {
+		lines = append(lines, fmt.Sprintf("unexpected: %v", validMove))
+	}
+	for _, mismatch := range diff.Mismatched {
+		lines = append(lines, fmt.Sprintf("mismatched: %v: %v", mismatch.ValidMove, mismatch.Reason))
+	}
+	for _, move := range diff.CoreMissing {
+		lines = append(lines, fmt.Sprintf("missing from checkers-core: %v-%v", ExportCoreLocation(move.From()), ExportCoreLocation(move.To())))
+	}
+	for _, validMove := range diff.CoreUnexpected {
+		lines = append(lines, fmt.Sprintf("not allowed by checkers-core: %v", validMove))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Compare the active team's validMoves with the moves generated from the
+// board by LegalMovePaths, route by route including captures and crowning,
+// and with the moves checkers-core generates, start and end only.  Other
+// variants than AMERICAN_CHECKERS are only checked against the local
+// rules.
+func ValidateValidMoves(gameState GameState) (diff ValidMovesDiff) {
+
+	if int(gameState.ActiveTeam) >= len(gameState.Teams) || gameState.ActiveTeam < 0 {
+		return
+	}
+	validMoves := gameState.Teams[gameState.ActiveTeam].AllValidMoves()
+	legalPaths := gameState.LegalMovePaths()
+
+	paths := map[string]MovePath{}
+	for _, path := range legalPaths {
+		paths[pathKey(path.Locations)] = path
+	}
+	offered := map[string]bool{}
+	for _, validMove := range validMoves {
+		key := pathKey(validMove.Path())
+		offered[key] = true
+		path, ok := paths[key]
+		if !ok {
+			diff.Unexpected = append(diff.Unexpected, validMove)
+			continue
+		}
+		if reason := pathMismatch(gameState, validMove, path); reason != "" {
+			diff.Mismatched = append(diff.Mismatched, MoveMismatch{ValidMove: validMove, Path: path, Reason: reason})
+		}
+	}
+	for _, path := range legalPaths {
+		if !offered[pathKey(path.Locations)] {
+			diff.Missing = append(diff.Missing, path)
+		}
+	}
+
+	// checkers-core only plays on an 8x8 board
+	if gameState.Variant != AMERICAN_CHECKERS {
+		return
+	}
+	coreMoves := gameState.Export().LegalMoves(GetCorePlayer(gameState.ActiveTeam))
+	for _, move := range coreMoves {
+		if _, err := FindValidMove(move, validMoves); err != nil {
+			if _, ambiguous := err.(*AmbiguousMoveError); !ambiguous {
+				diff.CoreMissing = append(diff.CoreMissing, move)
+			}
+		}
+	}
+	for _, validMove := range validMoves {
+		matched := false
+		for _, move := range coreMoves {
+			if EqualsCoreMove(validMove, move) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			diff.CoreUnexpected = append(diff.CoreUnexpected, validMove)
+		}
+	}
+	return
+
+}
+
+// Why a valid move with the same route as the path doesn't agree with it,
+// or "" if it does
+func pathMismatch(gameState GameState, validMove ValidMove, path MovePath) string {
+
+	captured := []int{}
+	for _, capture := range validMove.Captures {
+		if capture.TeamID < 0 || capture.TeamID >= len(gameState.Teams) ||
+			capture.PieceId < 0 || capture.PieceId >= len(gameState.Teams[capture.TeamID].Pieces) {
+			return fmt.Sprintf("capture of unknown piece %v of team %v", capture.PieceId, capture.TeamID)
+		}
+		piece := gameState.Teams[capture.TeamID].Pieces[capture.PieceId]
+		if piece.Captured {
+			return fmt.Sprintf("captures piece %v of team %v, which is already captured", capture.PieceId, capture.TeamID)
+		}
+		if TeamType(capture.TeamID) == gameState.ActiveTeam {
+			return fmt.Sprintf("captures its own piece on %v", piece.Location)
+		}
+		captured = append(captured, piece.Location)
+	}
+	expected := append([]int{}, path.Captured...)
+	sort.Ints(captured)
+	sort.Ints(expected)
+	if !intsEqual(captured, expected) {
+		return fmt.Sprintf("captures %v, the rules say %v", captured, expected)
+	}
+	if validMove.King != path.Crowned {
+		return fmt.Sprintf("king is %v, the rules say %v", validMove.King, path.Crowned)
+	}
+	return ""
+
+}
+
+func pathKey(locations []int) string {
+	return fmt.Sprintf("%v", locations)
+}
+
+func (game *Game) SetValidMovesCheck(check ValidMovesCheck) {
+	game.validMovesCheck = check
+}
+
+// Run the configured validMoves check on a game state where it's our turn,
+// and return true if we should refuse to vote on it.
+func (game *Game) refuseValidMoves(gameState GameState) bool {
+
+	if game.validMovesCheck == IGNORE_VALID_MOVES_MISMATCH {
+		return false
+	}
+	diff := ValidateValidMoves(gameState)
+	if diff.Empty() {
+		return false
+	}
+	game.warn("Server validMoves disagree with the rules", "fetchedGame", gameState.Number, "fetchedRev", gameState.Rev, "diff", diff)
+	if game.validMovesCheck == REFUSE_VALID_MOVES_MISMATCH {
+		game.warn("Refusing to vote", "fetchedRev", gameState.Rev)
+		return true
+	}
+	return false
+
+}
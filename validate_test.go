@@ -0,0 +1,75 @@
+package checkersbot
+
+import (
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+// Red has to jump 15x22, and the piece on 15 started on 11
+func validateTestGameState(t *testing.T) GameState {
+	gameState, err := NewGameStateFromHistory([]MoveHistory{
+		{Piece: -1, Team: RED_TEAM, Locations: []int{11, 15}},
+		{Piece: -1, Team: BLUE_TEAM, Locations: []int{22, 18}},
+	})
+	assert.True(t, err == nil)
+	assert.DeepEquals(t, validMoveKeys(gameState.Teams[RED_TEAM].AllValidMoves()), []string{"15:[22]"})
+	return gameState
+}
+
+func TestValidateValidMoves(t *testing.T) {
+
+	gameState := validateTestGameState(t)
+	diff := ValidateValidMoves(gameState)
+	assert.True(t, diff.Empty())
+
+	// the server offers a step instead of the compulsory jump
+	gameState.Teams[RED_TEAM].Pieces[10].ValidMoves = nil
+	gameState.Teams[RED_TEAM].Pieces[8].ValidMoves = []ValidMove{{Locations: []int{13}}}
+	diff = ValidateValidMoves(gameState)
+	assert.False(t, diff.Empty())
+	assert.Equals(t, len(diff.Missing), 1)
+	assert.DeepEquals(t, diff.Missing[0].Locations, []int{15, 22})
+	assert.Equals(t, len(diff.Unexpected), 1)
+	assert.Equals(t, diff.Unexpected[0].StartLocation, 9)
+	assert.Equals(t, len(diff.CoreMissing), 1)
+	assert.Equals(t, len(diff.CoreUnexpected), 1)
+	assert.Equals(t, len(diff.Mismatched), 0)
+
+}
+
+func TestValidateValidMovesCaptures(t *testing.T) {
+
+	gameState := validateTestGameState(t)
+
+	// right route, wrong piece captured.  checkers-core can't tell.
+	gameState.Teams[RED_TEAM].Pieces[10].ValidMoves[0].Captures = []Capture{{TeamID: int(BLUE_TEAM), PieceId: 0}}
+	diff := ValidateValidMoves(gameState)
+	assert.Equals(t, len(diff.Mismatched), 1)
+	assert.Equals(t, diff.Mismatched[0].ValidMove.StartLocation, 15)
+	assert.Equals(t, len(diff.Missing), 0)
+	assert.Equals(t, len(diff.Unexpected), 0)
+	assert.Equals(t, len(diff.CoreMissing), 0)
+	assert.Equals(t, len(diff.CoreUnexpected), 0)
+
+	gameState = validateTestGameState(t)
+	gameState.Teams[RED_TEAM].Pieces[10].ValidMoves[0].King = true
+	diff = ValidateValidMoves(gameState)
+	assert.Equals(t, len(diff.Mismatched), 1)
+
+}
+
+func TestRefuseValidMoves(t *testing.T) {
+
+	gameState := validateTestGameState(t)
+	gameState.Teams[RED_TEAM].Pieces[10].ValidMoves = nil
+
+	game := NewGame(RED_TEAM, nil)
+	assert.False(t, game.refuseValidMoves(gameState))
+	game.SetValidMovesCheck(LOG_VALID_MOVES_MISMATCH)
+	assert.False(t, game.refuseValidMoves(gameState))
+	game.SetValidMovesCheck(REFUSE_VALID_MOVES_MISMATCH)
+	assert.True(t, game.refuseValidMoves(gameState))
+	assert.False(t, game.refuseValidMoves(validateTestGameState(t)))
+
+}
@@ -0,0 +1,273 @@
+package checkersbot
+
+import (
+	"fmt"
+	"strings"
+
+	core "github.com/tleyden/checkers-core"
+)
+
+// The rules a game is played by
+type Variant int
+
+const (
+	// 8x8, men move and capture forwards only, kings move one square and
+	// any capture sequence may be chosen.  This is what the server plays.
+	AMERICAN_CHECKERS = Variant(iota)
+
+	// 10x10 with locations 1 to 50.  Men capture backwards too, kings fly
+	// and the sequence capturing the most pieces is compulsory.  White
+	// (BLUE) moves first.
+	INTERNATIONAL_DRAUGHTS
+)
+
+func (v Variant) String() string {
+	switch v {
+	case INTERNATIONAL_DRAUGHTS:
+		return "international"
+	default:
+		return "american"
+	}
+}
+
+func ParseVariant(name string) (Variant, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "american", "english", "checkers":
+		return AMERICAN_CHECKERS, nil
+	case "international", "draughts", "10x10":
+		return INTERNATIONAL_DRAUGHTS, nil
+	}
+	return AMERICAN_CHECKERS, fmt.Errorf("unknown variant %q, expected american or international", name)
+}
+
+func (v Variant) Geometry() Geometry {
+	switch v {
+	case INTERNATIONAL_DRAUGHTS:
+		return Geometry{Size: 10}
+	default:
+		return STANDARD_GEOMETRY
+	}
+}
+
+func (v Variant) InitialFEN() string {
+	switch v {
+	case INTERNATIONAL_DRAUGHTS:
+		return "W:W31-50:B1-20"
+	default:
+		return INITIAL_FEN
+	}
+}
+
+// Kings move and capture any distance along a diagonal
+func (v Variant) FlyingKings() bool {
+	return v == INTERNATIONAL_DRAUGHTS
+}
+
+func (v Variant) MenCaptureBackward() bool {
+	return v == INTERNATIONAL_DRAUGHTS
+}
+
+// Only the capture sequences taking the most pieces are legal
+func (v Variant) MajorityCapture() bool {
+	return v == INTERNATIONAL_DRAUGHTS
+}
+
+// In American checkers a man that reaches the far row is crowned and its
+// move ends there, even in the middle of a capture.  In international
+// draughts it carries on capturing and is only crowned if the move ends
+// on the far row.
+func (v Variant) CrowningEndsMove() bool {
+	return v == AMERICAN_CHECKERS
+}
+
+// A board of any variant, with the piece on each location.  core.Board is
+// 8x8 only, so the local rules use this for the other variants.
+type VariantBoard struct {
+	Variant Variant
+
+	// Indexed by location, 0 is unused
+	Pieces []core.Piece
+}
+
+func NewVariantBoard(variant Variant) VariantBoard {
+	return VariantBoard{
+		Variant: variant,
+		Pieces:  make([]core.Piece, variant.Geometry().Squares()+1),
+	}
+}
+
+// The piece on the location, EMPTY for locations off the board
+func (board VariantBoard) PieceAt(location int) core.Piece {
+	if location < 1 || location >= len(board.Pieces) {
+		return core.EMPTY
+	}
+	return board.Pieces[location]
+}
+
+// The AMERICAN_CHECKERS VariantBoard with the pieces of a core.Board
+func coreVariantBoard(board core.Board) VariantBoard {
+	variantBoard := NewVariantBoard(AMERICAN_CHECKERS)
+	for location := 1; location < len(variantBoard.Pieces); location++ {
+		variantBoard.Pieces[location] = board.PieceAt(GetCoreLocation(location))
+	}
+	return variantBoard
+}
+
+func (gamestate GameState) ExportVariantBoard() VariantBoard {
+	board := NewVariantBoard(gamestate.Variant)
+	for teamIndex, team := range gamestate.Teams {
+		for _, piece := range team.Pieces {
+			if piece.Captured || piece.Location < 1 || piece.Location >= len(board.Pieces) {
+				continue
+			}
+			switch {
+			case teamIndex == 0 && piece.King:
+				board.Pieces[piece.Location] = core.BLACK_KING
+			case teamIndex == 0:
+				board.Pieces[piece.Location] = core.BLACK
+			case piece.King:
+				board.Pieces[piece.Location] = core.RED_KING
+			default:
+				board.Pieces[piece.Location] = core.RED
+			}
+		}
+	}
+	return board
+}
+
+// Every legal move for the player under the board's variant, with full
+// multi-jump routes.  Jumps are compulsory and a jump carries on for as
+// long as the piece can keep jumping.
+func VariantLegalMovePaths(board VariantBoard, player core.Player) (paths []MovePath) {
+
+	jumps := []MovePath{}
+	steps := []MovePath{}
+	for location := 1; location < len(board.Pieces); location++ {
+		piece := board.PieceAt(location)
+		if !corePieceOwnedBy(piece, player) {
+			continue
+		}
+
+		jumps = append(jumps, board.jumpPaths(player, piece, location, []int{location}, nil)...)
+		if len(jumps) > 0 {
+			continue
+		}
+		steps = append(steps, board.stepPaths(piece, location)...)
+	}
+
+	if len(jumps) == 0 {
+		return steps
+	}
+	if !board.Variant.MajorityCapture() {
+		return jumps
+	}
+	most := 0
+	for _, jump := range jumps {
+		if len(jump.Captured) > most {
+			most = len(jump.Captured)
+		}
+	}
+	for _, jump := range jumps {
+		if len(jump.Captured) == most {
+			paths = append(paths, jump)
+		}
+	}
+	return
+
+}
+
+func (board VariantBoard) stepPaths(piece core.Piece, location int) (paths []MovePath) {
+	geometry := board.Variant.Geometry()
+	from := geometry.CoreLocation(location)
+	for _, direction := range pieceDirections(piece) {
+		for distance := 1; ; distance++ {
+			to := geometry.Location(core.NewLocation(from.Row()+distance*direction[0], from.Col()+distance*direction[1]))
+			if to == -1 || board.PieceAt(to) != core.EMPTY {
+				break
+			}
+			paths = append(paths, MovePath{
+				Locations: []int{location, to},
+				Crowned:   board.crowns(piece, to),
+			})
+			if !isKing(piece) || !board.Variant.FlyingKings() {
+				break
+			}
+		}
+	}
+	return
+}
+
+// Jumped pieces stay on the board until the move is over, so they can't
+// be jumped twice and block the way, while the square the piece started
+// on counts as empty.
+func (board VariantBoard) jumpPaths(player core.Player, piece core.Piece, location int, locations []int, captured []int) (paths []MovePath) {
+
+	geometry := board.Variant.Geometry()
+	start := locations[0]
+	empty := func(l int) bool {
+		return l != -1 && (board.PieceAt(l) == core.EMPTY || l == start)
+	}
+	flying := isKing(piece) && board.Variant.FlyingKings()
+	directions := pieceDirections(piece)
+	if !isKing(piece) && board.Variant.MenCaptureBackward() {
+		directions = pieceDirections(core.BLACK_KING)
+	}
+
+	from := geometry.CoreLocation(location)
+	for _, direction := range directions {
+		at := func(distance int) int {
+			return geometry.Location(core.NewLocation(from.Row()+distance*direction[0], from.Col()+distance*direction[1]))
+		}
+
+		distance := 1
+		for flying && empty(at(distance)) {
+			distance++
+		}
+		over := at(distance)
+		if over == -1 || !corePieceOwnedBy(board.PieceAt(over), opponentCorePlayer(player)) || containsInt(captured, over) {
+			continue
+		}
+
+		for distance++; empty(at(distance)); distance++ {
+			to := at(distance)
+			nextLocations := append(append([]int{}, locations...), to)
+			nextCaptured := append(append([]int{}, captured...), over)
+
+			if board.crowns(piece, to) && board.Variant.CrowningEndsMove() {
+				paths = append(paths, MovePath{Locations: nextLocations, Captured: nextCaptured, Crowned: true})
+			} else {
+				continuations := board.jumpPaths(player, piece, to, nextLocations, nextCaptured)
+				if len(continuations) == 0 {
+					paths = append(paths, MovePath{Locations: nextLocations, Captured: nextCaptured, Crowned: board.crowns(piece, to)})
+				}
+				paths = append(paths, continuations...)
+			}
+
+			if !flying {
+				break
+			}
+		}
+	}
+	return
+
+}
+
+// Whether a man of this piece's colour gets crowned on the location
+func (board VariantBoard) crowns(piece core.Piece, location int) bool {
+	geometry := board.Variant.Geometry()
+	row := geometry.CoreLocation(location).Row()
+	return (piece == core.BLACK && row == geometry.Size-1) || (piece == core.RED && row == 0)
+}
+
+func isKing(piece core.Piece) bool {
+	return piece == core.BLACK_KING || piece == core.RED_KING
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
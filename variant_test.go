@@ -0,0 +1,115 @@
+package checkersbot
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+	core "github.com/tleyden/checkers-core"
+)
+
+func pathStrings(paths []MovePath) []string {
+	strs := []string{}
+	for _, path := range paths {
+		strs = append(strs, path.String())
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+func internationalPaths(t *testing.T, fen string) []MovePath {
+	gameState, err := NewVariantGameStateFromFEN(INTERNATIONAL_DRAUGHTS, fen)
+	assert.True(t, err == nil)
+	return gameState.LegalMovePaths()
+}
+
+func TestParseVariant(t *testing.T) {
+	variant, err := ParseVariant("International")
+	assert.True(t, err == nil)
+	assert.Equals(t, variant, INTERNATIONAL_DRAUGHTS)
+	variant, err = ParseVariant("")
+	assert.True(t, err == nil)
+	assert.Equals(t, variant, AMERICAN_CHECKERS)
+	_, err = ParseVariant("chess")
+	assert.True(t, err != nil)
+}
+
+func TestVariantLegalMovePathsAmerican(t *testing.T) {
+
+	// the moves start and end where checkers-core's do on an 8x8 board
+	for _, fen := range []string{
+		INITIAL_FEN,
+		"B:W6,7,14,15:B2",
+		"B:W26,27:B22",
+		"W:WK27:B23,14",
+		"W:W18,K30:B14,K1,10,11",
+	} {
+		gameState := MustGameStateFromFEN(fen)
+		board := gameState.Export()
+		player := GetCorePlayer(gameState.ActiveTeam)
+		ends := map[string]bool{}
+		for _, path := range VariantLegalMovePaths(gameState.ExportVariantBoard(), player) {
+			ends[fmt.Sprintf("%v-%v", path.Locations[0], path.Locations[len(path.Locations)-1])] = true
+		}
+		coreEnds := map[string]bool{}
+		for _, move := range board.LegalMoves(player) {
+			coreEnds[fmt.Sprintf("%v-%v", ExportCoreLocation(move.From()), ExportCoreLocation(move.To()))] = true
+		}
+		assert.DeepEquals(t, ends, coreEnds)
+	}
+
+}
+
+func TestInternationalOpening(t *testing.T) {
+
+	gameState := NewReferee(INTERNATIONAL_DRAUGHTS).InitialGameState()
+	assert.Equals(t, gameState.ActiveTeam, BLUE_TEAM)
+	assert.Equals(t, len(gameState.Teams[RED_TEAM].Pieces), 20)
+	assert.Equals(t, len(gameState.Teams[BLUE_TEAM].Pieces), 20)
+	assert.Equals(t, len(gameState.Teams[BLUE_TEAM].AllValidMoves()), 9)
+	assert.Equals(t, len(gameState.Teams[RED_TEAM].AllValidMoves()), 0)
+
+}
+
+func TestInternationalFlyingKing(t *testing.T) {
+
+	// a king in the corner can go anywhere along the long diagonal
+	paths := internationalPaths(t, "W:WK46:B1")
+	assert.Equals(t, len(paths), 9)
+
+	// and capture from a distance, landing on any square beyond
+	paths = internationalPaths(t, "W:WK46:B32")
+	assert.DeepEquals(t, pathStrings(paths), []string{"46x10", "46x14", "46x19", "46x23", "46x28", "46x5"})
+	for _, path := range paths {
+		assert.DeepEquals(t, path.Captured, []int{32})
+	}
+
+}
+
+func TestInternationalCaptures(t *testing.T) {
+
+	// men capture backwards
+	paths := internationalPaths(t, "W:W28:B33")
+	assert.DeepEquals(t, pathStrings(paths), []string{"28x39"})
+
+	// taking two is compulsory when taking one is possible too
+	paths = internationalPaths(t, "W:W28:B11,22,23")
+	assert.DeepEquals(t, pathStrings(paths), []string{"28x17x6"})
+	assert.DeepEquals(t, paths[0].Captured, []int{22, 11})
+
+	// a man passing over the far row keeps capturing and isn't crowned
+	paths = internationalPaths(t, "W:W12:B8,9")
+	assert.DeepEquals(t, pathStrings(paths), []string{"12x3x14"})
+	assert.False(t, paths[0].Crowned)
+	paths = internationalPaths(t, "W:W12:B8")
+	assert.DeepEquals(t, pathStrings(paths), []string{"12x3"})
+	assert.True(t, paths[0].Crowned)
+
+}
+
+func TestVariantCoreLocation(t *testing.T) {
+	assert.Equals(t, GetVariantCoreLocation(INTERNATIONAL_DRAUGHTS, 50), core.NewLocation(9, 8))
+	assert.Equals(t, ExportVariantCoreLocation(INTERNATIONAL_DRAUGHTS, core.NewLocation(9, 0)), 46)
+	assert.Equals(t, GetVariantCoreLocation(AMERICAN_CHECKERS, 32), GetCoreLocation(32))
+}
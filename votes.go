@@ -1,11 +1,15 @@
 package checkersbot
 
+import (
+	"sort"
+)
+
 type OutgoingVotes struct {
 	Id        string                 `json:"_id"`
 	Rev       string                 `json:"_rev"`
 	Revisions map[string]interface{} `json:"_revisions"`
 	Channels  []interface{}          `json:"channels"` // ??
-	Moves     []VoteMove             `json:"moves"`    // ??
+	Moves     []VoteMove             `json:"moves"`    // only on the votes:checkers tally, see VoteTally
 	TeamId    TeamType               `json:"team"`
 	GameId    int                    `json:"game"`
 	Count     int                    `json:"count"`
@@ -14,6 +18,7 @@ type OutgoingVotes struct {
 	Locations []int                  `json:"locations"`
 }
 
+// The votes for one move in a VoteTally
 type VoteMove struct {
 	GameId    int      `json:"game"`
 	Count     int      `json:"count"`
@@ -22,3 +27,152 @@ type VoteMove struct {
 	TeamId    TeamType `json:"team"`
 	Turn      int      `json:"turn"`
 }
+
+// The votes:checkers doc, where the server adds up the crowd's votes for
+// the team whose turn it is.  Count is the number of votes cast, Moves has
+// each move voted for and its share.
+type VoteTally struct {
+	Id     string     `json:"_id"`
+	Rev    string     `json:"_rev"`
+	TeamId TeamType   `json:"team"`
+	GameId int        `json:"game"`
+	Turn   int        `json:"turn"`
+	Count  int        `json:"count"`
+	Moves  []VoteMove `json:"moves"`
+}
+
+// Whether the tally is for the turn the game state is waiting on
+func (tally VoteTally) ForGameState(gameState GameState) bool {
+	return tally.GameId == gameState.Number && tally.Turn == gameState.Turn && tally.TeamId == gameState.ActiveTeam
+}
+
+// The votes cast, from Count or, if the server left it out, the moves
+func (tally VoteTally) Total() int {
+	if tally.Count > 0 {
+		return tally.Count
+	}
+	total := 0
+	for _, move := range tally.Moves {
+		total += move.Count
+	}
+	return total
+}
+
+// The moves voted for, most votes first, ties in the server's order
+func (tally VoteTally) Ranked() []VoteMove {
+	ranked := append([]VoteMove{}, tally.Moves...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Count > ranked[j].Count
+	})
+	return ranked
+}
+
+// The move with the most votes, if any were cast
+func (tally VoteTally) Leading() (VoteMove, bool) {
+	ranked := tally.Ranked()
+	if len(ranked) == 0 || ranked[0].Count == 0 {
+		return VoteMove{}, false
+	}
+	return ranked[0], true
+}
+
+// The votes for the move with these locations, start location first
+func (tally VoteTally) CountFor(locations []int) int {
+	for _, move := range tally.Moves {
+		if intsEqual(move.Locations, locations) {
+			return move.Count
+		}
+	}
+	return 0
+}
+
+// The fraction of the votes cast for the move with these locations
+func (tally VoteTally) Share(locations []int) float64 {
+	total := tally.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(tally.CountFor(locations)) / float64(total)
+}
+
+// The valid move the crowd is leaning towards, to join the leading move.
+// Not ok unless the tally is for this turn and its leading move is valid.
+func (tally VoteTally) LeadingValidMove(gameState GameState) (ValidMove, bool) {
+	leading, ok := tally.Leading()
+	if !ok || !tally.ForGameState(gameState) {
+		return ValidMove{}, false
+	}
+	for _, validMove := range gameState.Teams[gameState.ActiveTeam].AllValidMoves() {
+		if intsEqual(validMove.Path(), leading.Locations) {
+			return validMove, true
+		}
+	}
+	return ValidMove{}, false
+}
+
+// Thinkers and lifecycle observers that implement this subscribe to the
+// vote tally.  Whenever the votes:checkers doc changes the game fetches it
+// and passes it on, whichever game and turn it's for, before it handles the
+// game doc from the same changes.  It's called from the game loop, while
+// Think may be running on another goroutine, so anything the two share
+// needs a lock.
+type VoteTallyObserver interface {
+	VoteTallyChanged(tally VoteTally)
+}
+
+func (game *Game) FetchVoteTally() (VoteTally, error) {
+	tally := VoteTally{}
+	err := game.gameServer().Retrieve(VOTES_DOC_ID, &tally)
+	return tally, err
+}
+
+// The last tally fetched for the VoteTallyObservers, if there is one
+func (game *Game) LatestVoteTally() (VoteTally, bool) {
+	game.voteTallyMutex.Lock()
+	defer game.voteTallyMutex.Unlock()
+	if game.voteTally == nil {
+		return VoteTally{}, false
+	}
+	return *game.voteTally, true
+}
+
+func (game *Game) voteTallyObservers() (observers []VoteTallyObserver) {
+	for _, observer := range game.optionalObservers() {
+		if observer, ok := observer.(VoteTallyObserver); ok {
+			observers = append(observers, observer)
+		}
+	}
+	return
+}
+
+// Fetch the tally when it has changed and someone has subscribed to it
+func (game *Game) handleVoteTallyChanges(changes Changes) {
+
+	if !hasDocChanged(changes, VOTES_DOC_ID) {
+		return
+	}
+	observers := game.voteTallyObservers()
+	if len(observers) == 0 {
+		return
+	}
+
+	tally, err := game.FetchVoteTally()
+	if err != nil {
+		game.logError("Error fetching vote tally", err)
+		return
+	}
+
+	game.voteTallyMutex.Lock()
+	seen := game.voteTally != nil && game.voteTally.Rev == tally.Rev
+	game.voteTally = &tally
+	game.voteTallyMutex.Unlock()
+	if seen {
+		return
+	}
+
+	game.debug("Vote tally changed", "tallyRev", tally.Rev, "tallyTurn", tally.Turn, "votes", tally.Total())
+	for _, observer := range observers {
+		observer.VoteTallyChanged(tally)
+	}
+
+}
@@ -0,0 +1,166 @@
+package checkersbot
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/couchbaselabs/go.assert"
+)
+
+const voteTallyJSON = `{"_id":"votes:checkers","_rev":"16-ebaa86d97e63940fddfdbd11a219e9e6","game":12,"turn":3,"team":0,"count":7,"moves":[
+{"game":12,"turn":3,"team":0,"piece":9,"locations":[9,13],"count":2},
+{"game":12,"turn":3,"team":0,"piece":10,"locations":[10,14],"count":4},
+{"game":12,"turn":3,"team":0,"piece":11,"locations":[11,15],"count":1}]}`
+
+type tallyRecorder struct {
+	BaseLifecycleObserver
+	mutex   sync.Mutex
+	tallies []VoteTally
+}
+
+func (r *tallyRecorder) VoteTallyChanged(tally VoteTally) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tallies = append(r.tallies, tally)
+}
+
+func TestVoteTally(t *testing.T) {
+
+	tally := VoteTally{}
+	assert.True(t, json.Unmarshal([]byte(voteTallyJSON), &tally) == nil)
+	assert.Equals(t, tally.Total(), 7)
+
+	leading, ok := tally.Leading()
+	assert.True(t, ok)
+	assert.DeepEquals(t, leading.Locations, []int{10, 14})
+	assert.Equals(t, tally.Ranked()[1].Count, 2)
+	assert.Equals(t, tally.CountFor([]int{11, 15}), 1)
+	assert.Equals(t, tally.CountFor([]int{12, 16}), 0)
+	assert.Equals(t, tally.Share([]int{10, 14}), 4.0/7.0)
+
+	gameState := NewReferee(AMERICAN_CHECKERS).InitialGameState()
+	gameState.Number = 12
+	gameState.Turn = 3
+	assert.True(t, tally.ForGameState(gameState))
+	validMove, ok := tally.LeadingValidMove(gameState)
+	assert.True(t, ok)
+	assert.DeepEquals(t, validMove.Path(), []int{10, 14})
+
+	gameState.Turn = 4
+	_, ok = tally.LeadingValidMove(gameState)
+	assert.False(t, ok)
+
+	tally.Count = 0
+	assert.Equals(t, tally.Total(), 7)
+	_, ok = VoteTally{}.Leading()
+	assert.False(t, ok)
+
+}
+
+func TestVoteTallyFeed(t *testing.T) {
+
+	server := newReplayServer()
+	tally := VoteTally{}
+	json.Unmarshal([]byte(voteTallyJSON), &tally)
+	_, _, err := server.Insert(tally)
+	assert.True(t, err == nil)
+
+	game := NewGame(RED_TEAM, nil)
+	game.server = server
+	fetched, err := game.FetchVoteTally()
+	assert.True(t, err == nil)
+	assert.Equals(t, fetched.Turn, 3)
+	assert.Equals(t, len(fetched.Moves), 3)
+
+	changes := Changes{"results": []interface{}{
+		map[string]interface{}{"id": VOTES_DOC_ID, "changes": []interface{}{map[string]interface{}{"rev": "1-replay"}}},
+	}}
+
+	// nobody subscribed, nothing fetched
+	game.handleVoteTallyChanges(changes)
+	_, ok := game.LatestVoteTally()
+	assert.False(t, ok)
+
+	recorder := &tallyRecorder{}
+	game.AddLifecycleObserver(recorder)
+	game.handleVoteTallyChanges(changes)
+	game.handleVoteTallyChanges(changes)
+	game.handleVoteTallyChanges(Changes{})
+	assert.Equals(t, len(recorder.tallies), 1)
+	assert.Equals(t, recorder.tallies[0].Total(), 7)
+
+	latest, ok := game.LatestVoteTally()
+	assert.True(t, ok)
+	assert.Equals(t, latest.Rev, "1-replay")
+
+	tally.Moves[0].Count = 5
+	server.Edit(tally)
+	game.handleVoteTallyChanges(changes)
+	assert.Equals(t, len(recorder.tallies), 2)
+	leading, _ := recorder.tallies[1].Leading()
+	assert.DeepEquals(t, leading.Locations, []int{9, 13})
+
+}
+
+// Joins the leading move of the last tally it was given
+type crowdThinker struct {
+	mutex sync.Mutex
+	tally VoteTally
+}
+
+func (c *crowdThinker) VoteTallyChanged(tally VoteTally) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tally = tally
+}
+
+func (c *crowdThinker) Think(gameState GameState) (ValidMove, bool) {
+	c.mutex.Lock()
+	tally := c.tally
+	c.mutex.Unlock()
+	return tally.LeadingValidMove(gameState)
+}
+
+type tallyEventRecorder struct {
+	recordingObserver
+}
+
+func (r *tallyEventRecorder) VoteTallyChanged(tally VoteTally) {
+	r.record("tally %v", tally.Turn)
+}
+
+func TestVoteTallyBeforeGameDoc(t *testing.T) {
+
+	gameState := NewReferee(AMERICAN_CHECKERS).InitialGameState()
+	gameState.Number = 12
+	gameState.Turn = 3
+	gameState.Rev = "1-a"
+
+	server := newReplayServer()
+	server.setGameState(gameState)
+	tally := VoteTally{}
+	json.Unmarshal([]byte(voteTallyJSON), &tally)
+	server.Insert(tally)
+
+	thinker := &crowdThinker{}
+	game := NewGame(RED_TEAM, thinker)
+	game.server = server
+	game.user = User{Id: "user:1", TeamId: RED_TEAM}
+
+	// the tally and the game doc arrive in the same batch
+	changes := replayChanges(1, gameState.Rev)
+	changes["results"] = append(changes["results"].([]interface{}),
+		map[string]interface{}{"id": VOTES_DOC_ID, "changes": []interface{}{map[string]interface{}{"rev": "1-replay"}}},
+	)
+	recorder := &tallyEventRecorder{}
+	game.AddLifecycleObserver(recorder)
+	movesChan := make(chan ValidMove, 1)
+	game.handleFeedChanges(changes, movesChan)
+	validMove, ok := game.awaitReplayMove(movesChan)
+	assert.True(t, ok)
+	assert.DeepEquals(t, validMove.Path(), []int{10, 14})
+	assert.Equals(t, recorder.Events()[0], "tally 3")
+	assert.Equals(t, recorder.Events()[1], "started 12")
+
+}
@@ -0,0 +1,110 @@
+package checkersbot
+
+import (
+	"math/rand"
+
+	"github.com/couchbaselabs/logg"
+	core "github.com/tleyden/checkers-core"
+)
+
+// Zobrist hashing of core.Board positions.  Each (piece type, square)
+// pair gets a random 64 bit key, and the hash of a position is the xor of
+// the keys of every occupied square, plus one more key when red is to move.
+// Since xor is its own inverse, a move only has to toggle the squares it
+// touches rather than rehash the whole board.
+type ZobristHasher struct {
+	pieceKeys [4][32]uint64
+	redToMove uint64
+}
+
+// Create a hasher with keys drawn from the given seed.  Hashes are only
+// comparable between hashers created with the same seed.
+func NewZobristHasher(seed int64) *ZobristHasher {
+	random := rand.New(rand.NewSource(seed))
+	hasher := &ZobristHasher{}
+	for pieceIndex := range hasher.pieceKeys {
+		for square := range hasher.pieceKeys[pieceIndex] {
+			hasher.pieceKeys[pieceIndex][square] = random.Uint64()
+		}
+	}
+	hasher.redToMove = random.Uint64()
+	return hasher
+}
+
+// Hash the whole board from scratch
+func (hasher *ZobristHasher) Hash(board core.Board, player core.Player) (hash uint64) {
+	for location := 1; location <= 32; location++ {
+		hash ^= hasher.SquareKey(board.PieceAt(GetCoreLocation(location)), location)
+	}
+	if player == core.RED_PLAYER {
+		hash ^= hasher.redToMove
+	}
+	return
+}
+
+// The key for a piece sitting on a 1-32 location, zero for empty squares
+func (hasher *ZobristHasher) SquareKey(piece core.Piece, location int) uint64 {
+	if piece == core.EMPTY || location < 1 || location > 32 {
+		return 0
+	}
+	return hasher.pieceKeys[piece-core.BLACK][location-1]
+}
+
+// Apply the move to the board and update the hash incrementally, toggling
+// only the squares the move changed (start, end, captured pieces and any
+// crowning) and the side to move.  If the jumped pieces can't be worked
+// out, eg, for a move that isn't legal, the new board is hashed in full.
+func (hasher *ZobristHasher) ApplyMove(board core.Board, hash uint64, player core.Player, move core.Move) (core.Board, uint64) {
+
+	nextBoard := board.ApplyMove(player, move)
+	captured, ok := capturedLocations(board, nextBoard, player, move)
+	if !ok {
+		logg.LogTo("CHECKERSBOT", "No jump from %v to %v, rehashing the board", ExportCoreLocation(move.From()), ExportCoreLocation(move.To()))
+		return nextBoard, hasher.Hash(nextBoard, opponentCorePlayer(player))
+	}
+
+	from, to := move.From(), move.To()
+	fromLocation, toLocation := ExportCoreLocation(from), ExportCoreLocation(to)
+
+	// the piece leaves its start and lands, crowned or not, on the end
+	hash ^= hasher.SquareKey(board.PieceAt(from), fromLocation)
+	hash ^= hasher.SquareKey(nextBoard.PieceAt(to), toLocation)
+
+	for _, location := range captured {
+		hash ^= hasher.SquareKey(board.PieceAt(GetCoreLocation(location)), location)
+	}
+
+	hash ^= hasher.redToMove
+	return nextBoard, hash
+
+}
+
+// The locations of the pieces a move jumped, none for a step.  When more
+// than one jump sequence ends on the same square, it's the one whose
+// captured squares the move left empty.  Not ok if no jump sequence fits.
+func capturedLocations(board, nextBoard core.Board, player core.Player, move core.Move) (captured []int, ok bool) {
+
+	from, to := move.From(), move.To()
+	if abs(to.Row()-from.Row()) == 1 {
+		return nil, true
+	}
+	toLocation := ExportCoreLocation(to)
+	fromLocation := ExportCoreLocation(from)
+	for _, path := range VariantLegalMovePaths(coreVariantBoard(board), player) {
+		if path.Locations[0] != fromLocation || path.Locations[len(path.Locations)-1] != toLocation {
+			continue
+		}
+		emptied := true
+		for _, location := range path.Captured {
+			if nextBoard.PieceAt(GetCoreLocation(location)) != core.EMPTY {
+				emptied = false
+				break
+			}
+		}
+		if emptied {
+			return path.Captured, true
+		}
+	}
+	return nil, false
+
+}